@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	"goyave.dev/gyv/internal/command"
+	"goyave.dev/gyv/internal/inject"
+)
+
+// Clean command removing all cached plugins.
+type Clean struct{}
+
+// BuildCobraCommand builds the cobra command for this action
+func (c *Clean) BuildCobraCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clean",
+		Short: "Remove all cached plugins",
+		Long:  "Command to remove all the plugins stored in the plugin cache.",
+		RunE:  command.GenerateRunFunc(c),
+	}
+}
+
+// BuildSurvey builds a survey for this action
+func (c *Clean) BuildSurvey() ([]*survey.Question, error) {
+	return []*survey.Question{}, nil
+}
+
+// Execute the command's behavior
+func (c *Clean) Execute() error {
+	cache, err := inject.NewCache()
+	if err != nil {
+		return err
+	}
+
+	if err := cache.Clean(); err != nil {
+		return err
+	}
+
+	fmt.Println("✅ Plugin cache cleaned!")
+
+	return nil
+}
+
+// Validate checks if required flags are definded
+func (c *Clean) Validate() error {
+	return nil
+}
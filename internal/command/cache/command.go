@@ -0,0 +1,28 @@
+package cache
+
+import (
+	"goyave.dev/gyv/internal/command"
+
+	"github.com/spf13/cobra"
+)
+
+// BuildCommand builds a parent command for all cache-related subcommands
+func BuildCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Plugin build cache operations",
+		Long: `Command for operations on the cache storing the plugins compiled for the commands running the project's code.
+A cached plugin is reused as long as the project's sources, go.mod, go.sum and the Go version don't change.`,
+	}
+
+	commands := []command.Command{
+		&Info{},
+		&Clean{},
+	}
+
+	for _, c := range commands {
+		cmd.AddCommand(c.BuildCobraCommand())
+	}
+
+	return cmd
+}
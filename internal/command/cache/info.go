@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	"goyave.dev/gyv/internal/command"
+	"goyave.dev/gyv/internal/inject"
+)
+
+// Info command displaying the location and size of the plugin cache.
+type Info struct{}
+
+// BuildCobraCommand builds the cobra command for this action
+func (c *Info) BuildCobraCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "info",
+		Short: "Show plugin cache information",
+		Long:  "Command to display the location of the plugin cache, the number of cached plugins and their total size.",
+		RunE:  command.GenerateRunFunc(c),
+	}
+}
+
+// BuildSurvey builds a survey for this action
+func (c *Info) BuildSurvey() ([]*survey.Question, error) {
+	return []*survey.Question{}, nil
+}
+
+// Execute the command's behavior
+func (c *Info) Execute() error {
+	cache, err := inject.NewCache()
+	if err != nil {
+		return err
+	}
+
+	info, err := cache.Info()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("📁 Directory:", info.Directory)
+	fmt.Println("🧩 Cached plugins:", info.Entries)
+	fmt.Println("💾 Size:", formatSize(info.Size))
+
+	return nil
+}
+
+// Validate checks if required flags are definded
+func (c *Info) Validate() error {
+	return nil
+}
+
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
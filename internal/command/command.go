@@ -1,12 +1,14 @@
 package command
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/Masterminds/semver"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"golang.org/x/mod/modfile"
 	"goyave.dev/gyv/internal/mod"
 )
@@ -42,7 +44,7 @@ func GenerateRunFunc(c Command) func(*cobra.Command, []string) error {
 			consumedFlags += consumed
 		}
 
-		if cmd.Flags().NFlag()-consumedFlags == 0 {
+		if changedLocalFlags(cmd)-consumedFlags == 0 {
 			questions, err := c.BuildSurvey()
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "❌ %s\n", err.Error())
@@ -54,18 +56,59 @@ func GenerateRunFunc(c Command) func(*cobra.Command, []string) error {
 				return nil
 			}
 
+			if c, ok := c.(EnvCommand); ok {
+				if err := c.AskEnv(); err != nil {
+					fmt.Fprintf(os.Stderr, "❌ %s\n", err.Error())
+					return nil
+				}
+			}
+
 		} else if err := c.Validate(); err != nil {
 			return err
 		}
 
 		if err := c.Execute(); err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %s\n", err.Error())
+			exitErr := &ExitError{}
+			if errors.As(err, &exitErr) {
+				ExitCode = exitErr.Code
+			}
 		}
 
 		return nil
 	}
 }
 
+// changedLocalFlags returns the number of flags of the given command set by the user.
+// The global flags (e.g.: "env", "backend") are not counted: they don't replace the survey.
+func changedLocalFlags(cmd *cobra.Command) int {
+	changed := 0
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		if flag.Changed {
+			changed++
+		}
+	})
+	return changed
+}
+
+// ExitCode the exit code of gyv, set when a command returns an ExitError.
+var ExitCode = 0
+
+// ExitError an error making gyv exit with the given non-zero code,
+// for commands used in scripts (e.g.: a check failing in CI).
+type ExitError struct {
+	Err  error
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
 // ProjectPathCommand shared composition struct for commands
 // using a Goyave project path.
 // All commands compositing with this one should call "setup()"
@@ -73,6 +116,7 @@ func GenerateRunFunc(c Command) func(*cobra.Command, []string) error {
 // Commands using ProjectPathCommand should have a project path flag
 // and survey entry.
 type ProjectPathCommand struct {
+	ModFile       *modfile.File
 	GoyaveMod     *modfile.Require
 	GoyaveVersion *semver.Version
 	ProjectPath   string
@@ -81,7 +125,8 @@ type ProjectPathCommand struct {
 // Setup ensure the `ProjectPath` field is correctly set.
 // If `ProjectPath` is empty at the time `Setup()` is called, its value
 // will be set to `fs.FindParentModule()`.
-// The project's `go.mod` file is parsed and put into the `GoyaveMod` field.
+// The project's `go.mod` file is parsed and put into the `ModFile` field.
+// The Goyave requirement is put into the `GoyaveMod` field.
 // The Goyave framework version is parsed and put into the `GoyaveVersion` field.
 func (c *ProjectPathCommand) Setup() (int, error) {
 	consumedFlags := 1
@@ -96,6 +141,7 @@ func (c *ProjectPathCommand) Setup() (int, error) {
 	if err != nil {
 		return consumedFlags, err
 	}
+	c.ModFile = modFile
 
 	c.GoyaveMod = mod.FindGoyaveRequire(modFile)
 	if c.GoyaveMod == nil {
@@ -1,11 +1,22 @@
 package create
 
 import (
+	"fmt"
+
 	"goyave.dev/gyv/internal/command"
 
+	"github.com/Masterminds/semver"
 	"github.com/spf13/cobra"
 )
 
+var (
+	minimumGORMGoyaveVersion = semver.MustParse("v3.0.0")
+
+	// ErrUnsupportedGoyaveVersion returned when trying to generate a resource
+	// relying on GORM v2 in a Goyave project using an older version of the framework.
+	ErrUnsupportedGoyaveVersion = fmt.Errorf("Unsupported Goyave version. Minimum version: %s", minimumGORMGoyaveVersion.Original())
+)
+
 // BuildCommand builds a parent command for all creation-related subcommands
 func BuildCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -19,6 +30,8 @@ func BuildCommand() *cobra.Command {
 		&Controller{},
 		&Middleware{},
 		&Model{},
+		&CRUD{},
+		&Migration{},
 	}
 
 	for _, c := range commands {
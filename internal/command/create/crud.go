@@ -0,0 +1,167 @@
+package create
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"goyave.dev/gyv/internal/command"
+	"goyave.dev/gyv/internal/fs"
+	"goyave.dev/gyv/internal/stub"
+)
+
+// resourceNameRegex the resource names producing valid Go package and type names.
+var resourceNameRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// CRUD command for full resource generation (model, controller and validation rule sets)
+type CRUD struct {
+	command.ProjectPathCommand
+	Name string
+}
+
+// BuildCobraCommand builds the cobra command for this action
+func (c *CRUD) BuildCobraCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "crud",
+		Short: "Create a Goyave CRUD resource",
+		Long: `Command to create a full Goyave CRUD resource: a model, a controller
+with Index, Show, Store, Update and Destroy handlers, and validation rule sets.
+Only the name flag is required. The project-path is optional.
+If project-path is not specified, the nearest directory containing a go.mod file importing Goyave will be used.`,
+		RunE: command.GenerateRunFunc(c),
+	}
+
+	c.setFlags(cmd.Flags())
+
+	return cmd
+}
+
+// BuildSurvey builds a survey for this action
+func (c *CRUD) BuildSurvey() ([]*survey.Question, error) {
+	return []*survey.Question{
+		{
+			Name:   "Name",
+			Prompt: &survey.Input{Message: "Resource name"},
+			Validate: survey.ComposeValidators(survey.Required, func(ans interface{}) error {
+				return validateResourceName(ans.(string))
+			}),
+		},
+	}, nil
+}
+
+// Execute the command's behavior
+func (c *CRUD) Execute() error {
+	if c.GoyaveVersion.LessThan(minimumGORMGoyaveVersion) {
+		return ErrUnsupportedGoyaveVersion
+	}
+	if c.GoyaveVersion.Major() >= 5 {
+		return fmt.Errorf("CRUD generation is not supported for Goyave %s yet", c.GoyaveVersion.Original())
+	}
+
+	packageName := strings.ToLower(c.Name)
+	modelName := strings.Title(c.Name)
+
+	modelPath, err := fs.CreateModelPath(c.Name, c.ProjectPath, c.GoyaveVersion)
+	if err != nil {
+		return err
+	}
+
+	controllerPath, err := fs.CreateControllerPath(packageName, c.ProjectPath, c.GoyaveVersion)
+	if err != nil {
+		return err
+	}
+
+	resources := []struct {
+		stubPath string
+		folder   string
+		name     string
+	}{
+		{stub.CRUDModel, modelPath, packageName},
+		{stub.CRUDController, controllerPath, packageName},
+		{stub.CRUDRequest, controllerPath, "request"},
+	}
+
+	for _, r := range resources {
+		filePath := fmt.Sprintf("%s%c%s.go", r.folder, os.PathSeparator, r.name)
+		if _, err := os.Stat(filePath); err == nil {
+			return fmt.Errorf("%q already exists", filePath)
+		}
+	}
+
+	modelDirectory, err := filepath.Rel(c.ProjectPath, modelPath)
+	if err != nil {
+		return err
+	}
+
+	data := stub.Data{
+		"GoyaveImportPath": c.GoyaveMod.Mod.Path,
+		"ModelImportPath":  c.ModFile.Module.Mod.Path + "/" + filepath.ToSlash(modelDirectory),
+		"ModelName":        modelName,
+		"PackageName":      packageName,
+		"VariableName":     strings.ToLower(modelName[:1]) + modelName[1:],
+	}
+
+	for _, r := range resources {
+		stubPath, err := stub.GenerateStubVersionPath(r.stubPath, c.GoyaveVersion)
+		if err != nil {
+			return err
+		}
+
+		templateData, err := stub.Load(stubPath, data)
+		if err != nil {
+			return err
+		}
+
+		if err := fs.CreateResourceFile(r.folder, r.name, templateData.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("✅ CRUD resource created!")
+	fmt.Printf("➡️ Register the routes using the \"%s\" controller and its \"StoreRequest\" and \"UpdateRequest\" rule sets\n", packageName)
+	fmt.Println("➡️ Register the routes of a single resource with a numeric parameter: \"/{id:[0-9]+}\"")
+
+	return nil
+}
+
+// Validate checks if required flags are definded
+func (c *CRUD) Validate() error {
+	if c.Name == "" {
+		return errors.New("required flag(s) \"name\"")
+	}
+
+	return validateResourceName(c.Name)
+}
+
+// validateResourceName checks that the given name can be used as
+// a package name once lowercased and as a type name once capitalized.
+func validateResourceName(name string) error {
+	if !resourceNameRegex.MatchString(name) || token.IsKeyword(strings.ToLower(name)) {
+		return fmt.Errorf("invalid resource name %q: it must start with a letter, only contain letters, digits and underscores and not be a Go keyword", name)
+	}
+	return nil
+}
+
+func (c *CRUD) setFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(
+		&c.Name,
+		"name",
+		"n",
+		"",
+		"The name of the resource to generate",
+	)
+	flags.StringVarP(
+		&c.ProjectPath,
+		"project-path",
+		"p",
+		"",
+		"The path to the Goyave project root",
+	)
+}
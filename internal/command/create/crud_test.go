@@ -0,0 +1,47 @@
+package create
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateResourceName(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		name  string
+		valid bool
+	}{
+		{name: "user", valid: true},
+		{name: "User", valid: true},
+		{name: "blogPost", valid: true},
+		{name: "order_item", valid: true},
+		{name: "v2", valid: true},
+		{name: "Type", valid: false},
+		{name: "func", valid: false},
+		{name: "RANGE", valid: false},
+		{name: "2fa", valid: false},
+		{name: "_user", valid: false},
+		{name: "blog-post", valid: false},
+		{name: "blog post", valid: false},
+		{name: "../user", valid: false},
+		{name: "utilisé", valid: false},
+		{name: "", valid: false},
+	}
+	for _, c := range cases {
+		err := validateResourceName(c.name)
+		if c.valid {
+			assert.Nil(err, c.name)
+		} else {
+			assert.NotNil(err, c.name)
+		}
+	}
+
+	crud := &CRUD{}
+	assert.NotNil(crud.Validate())
+	crud.Name = "select"
+	assert.NotNil(crud.Validate())
+	crud.Name = "product"
+	assert.Nil(crud.Validate())
+}
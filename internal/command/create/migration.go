@@ -0,0 +1,180 @@
+package create
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"goyave.dev/gyv/internal/command"
+	"goyave.dev/gyv/internal/config"
+	"goyave.dev/gyv/internal/fs"
+	"goyave.dev/gyv/internal/stub"
+)
+
+const (
+	migrationTimestampFormat = "20060102150405"
+	migrationRegistryName    = "migration"
+)
+
+var (
+	migrationNameSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+	migrationFileRegex     = regexp.MustCompile(`^[0-9]{14}_(.+)\.go$`)
+)
+
+// Migration command for versioned migration generation
+type Migration struct {
+	command.ProjectPathCommand
+	MigrationName string
+}
+
+// BuildCobraCommand builds the cobra command for this action
+func (c *Migration) BuildCobraCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migration",
+		Short: "Create a versioned migration",
+		Long: `Command to create a timestamped versioned migration in "database/migration", or in the
+directory set by "database.migrationRoot" in gyv.json.
+The migration registry is generated alongside the first migration.
+Only the name flag is required. The project-path is optional.
+If project-path is not specified, the nearest directory containing a go.mod file importing Goyave will be used.`,
+		RunE: command.GenerateRunFunc(c),
+	}
+
+	c.setFlags(cmd.Flags())
+
+	return cmd
+}
+
+// BuildSurvey builds a survey for this action
+func (c *Migration) BuildSurvey() ([]*survey.Question, error) {
+	return []*survey.Question{
+		{
+			Name:     "MigrationName",
+			Prompt:   &survey.Input{Message: "Migration name (e.g.: add_status_to_orders)"},
+			Validate: survey.Required,
+		},
+	}, nil
+}
+
+// Execute the command's behavior
+func (c *Migration) Execute() error {
+	if c.GoyaveVersion.LessThan(minimumGORMGoyaveVersion) {
+		return ErrUnsupportedGoyaveVersion
+	}
+
+	name := normalizeMigrationName(c.MigrationName)
+	if name == "" {
+		return fmt.Errorf("invalid migration name %q", c.MigrationName)
+	}
+
+	project, err := config.LoadProject(c.ProjectPath)
+	if err != nil {
+		return err
+	}
+	folderPath := fs.CreateMigrationPath(c.ProjectPath, project.Database.MigrationDirectory())
+	registryExists, err := checkMigrationDirectory(folderPath, name)
+	if err != nil {
+		return err
+	}
+
+	if !registryExists {
+		if err := c.createResource(stub.MigrationRegistry, folderPath, migrationRegistryName, stub.Data{}); err != nil {
+			return err
+		}
+		fmt.Println("✅ Migration registry created!")
+	}
+
+	// The timestamp makes the function names unique, even if
+	// different migration names give the same camel-cased name.
+	timestamp := time.Now().UTC().Format(migrationTimestampFormat)
+	functionName := timestamp
+	for _, part := range strings.Split(name, "_") {
+		functionName += strings.Title(part)
+	}
+
+	migrationName := timestamp + "_" + name
+	err = c.createResource(stub.MigrationFile, folderPath, migrationName, stub.Data{
+		"MigrationName": migrationName,
+		"FunctionName":  functionName,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("✅ Migration created!")
+
+	return nil
+}
+
+// normalizeMigrationName converts the given name to snake case, keeping only
+// lowercase letters and digits. Returns an empty string if nothing is left.
+func normalizeMigrationName(name string) string {
+	return strings.Trim(migrationNameSeparator.ReplaceAllString(strings.ToLower(name), "_"), "_")
+}
+
+// checkMigrationDirectory returns an error if the given directory already contains
+// a migration with the given normalized name, and whether it contains the migration registry.
+// A missing directory is considered empty.
+func checkMigrationDirectory(folderPath, name string) (bool, error) {
+	entries, err := os.ReadDir(folderPath)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	registryExists := false
+	for _, e := range entries {
+		if e.Name() == migrationRegistryName+".go" {
+			registryExists = true
+		}
+		if m := migrationFileRegex.FindStringSubmatch(e.Name()); m != nil && m[1] == name {
+			return false, fmt.Errorf("a migration named %q already exists: %s", name, e.Name())
+		}
+	}
+	return registryExists, nil
+}
+
+func (c *Migration) createResource(stubPath, folderPath, name string, data stub.Data) error {
+	stubPath, err := stub.GenerateStubVersionPath(stubPath, c.GoyaveVersion)
+	if err != nil {
+		return err
+	}
+
+	data["GoyaveImportPath"] = c.GoyaveMod.Mod.Path
+	templateData, err := stub.Load(stubPath, data)
+	if err != nil {
+		return err
+	}
+
+	return fs.CreateResourceFile(folderPath, name, templateData.Bytes())
+}
+
+// Validate checks if required flags are definded
+func (c *Migration) Validate() error {
+	if c.MigrationName == "" {
+		return errors.New("required flag(s) \"name\"")
+	}
+
+	return nil
+}
+
+func (c *Migration) setFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(
+		&c.MigrationName,
+		"name",
+		"n",
+		"",
+		"The name of the migration to generate (e.g.: add_status_to_orders)",
+	)
+	flags.StringVarP(
+		&c.ProjectPath,
+		"project-path",
+		"p",
+		"",
+		"The path to the Goyave project root",
+	)
+}
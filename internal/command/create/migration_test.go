@@ -0,0 +1,77 @@
+package create
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeMigrationName(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		name     string
+		expected string
+	}{
+		{name: "add_status_to_orders", expected: "add_status_to_orders"},
+		{name: "AddStatus", expected: "addstatus"},
+		{name: "Add status to orders", expected: "add_status_to_orders"},
+		{name: "add-status--to.orders", expected: "add_status_to_orders"},
+		{name: "__create users table!", expected: "create_users_table"},
+		{name: "v2 index", expected: "v2_index"},
+		{name: "créer", expected: "cr_er"},
+		{name: "-_-", expected: ""},
+		{name: "", expected: ""},
+	}
+	for _, c := range cases {
+		assert.Equal(c.expected, normalizeMigrationName(c.name), c.name)
+	}
+}
+
+func TestCheckMigrationDirectory(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := os.MkdirTemp("", "gyv-migration")
+	if !assert.Nil(err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	registryExists, err := checkMigrationDirectory(filepath.Join(dir, "missing"), "create_users")
+	assert.Nil(err)
+	assert.False(registryExists)
+
+	for _, name := range []string{"20220101120000_create_users.go", "notes.go", "create_orders.go"} {
+		if !assert.Nil(os.WriteFile(filepath.Join(dir, name), []byte("package migration\n"), 0644)) {
+			return
+		}
+	}
+
+	cases := []struct {
+		name      string
+		duplicate bool
+	}{
+		{name: "create_users", duplicate: true},
+		{name: "create_orders", duplicate: false},
+		{name: "create", duplicate: false},
+		{name: "notes", duplicate: false},
+	}
+	for _, c := range cases {
+		_, err := checkMigrationDirectory(dir, c.name)
+		if c.duplicate {
+			assert.NotNil(err, c.name)
+		} else {
+			assert.Nil(err, c.name)
+		}
+	}
+
+	registryExists, err = checkMigrationDirectory(dir, "create_orders")
+	assert.Nil(err)
+	assert.False(registryExists)
+
+	assert.Nil(os.WriteFile(filepath.Join(dir, migrationRegistryName+".go"), []byte("package migration\n"), 0644))
+	registryExists, err = checkMigrationDirectory(dir, "create_orders")
+	assert.Nil(err)
+	assert.True(registryExists)
+}
@@ -15,14 +15,18 @@ import (
 	"goyave.dev/gyv/internal/mod"
 )
 
-const (
-	defaultZipFileName = "goyave_template.zip"
-)
-
 // Project command for project generation
 type Project struct {
 	GoyaveVersion string
 	ModuleName    string
+	Template      string
+
+	// Ref a branch or commit SHA of the template to use instead of a version.
+	Ref string
+	// IncludePrerelease allows the version constraints to select prereleases.
+	IncludePrerelease bool
+
+	command.TemplateSourceCommand
 }
 
 // BuildCobraCommand builds the cobra command for this action
@@ -32,7 +36,21 @@ func (c *Project) BuildCobraCommand() *cobra.Command {
 		Short: "Create a Goyave project",
 		Long: `Command to create Goyave project.
 You need go and git to be installed on your system in order de run this command.
-The flags --module-name and --goyave-version are required.`,
+The flags --module-name and --goyave-version are required.
+The Goyave version can be an exact version (e.g.: v4.0.0), a constraint (e.g.: "^4", "~4.3") or "latest".
+Constraints only select prereleases with --include-prerelease. Instead of a version, --ref creates
+the project from a branch or a commit SHA of the template.
+Downloaded templates are cached, so a project can be created offline with a version that was already used.
+Instead of the official template, the --template flag can designate a local template: a directory,
+a .zip or .tar.gz archive (its root directory, if any, is removed) or a file:// URL.
+The --template-repo flag selects another template repository, hosted on GitHub or, with the --api-url flag, on a
+GitHub Enterprise or Gitea-compatible server. Private repositories on github.com are accessed using the token of the
+GITHUB_TOKEN environment variable. Otherwise, the token associated with the API host in the gyv credentials file is used
+(e.g.: "~/.config/gyv/credentials.json" on Linux, containing {"github.example.org": "<token>"}).
+Tokens are only sent to the API host.
+With --source proxy, the template module (--template-module) is retrieved through the Go module proxy protocol instead,
+honouring the GOPROXY, GONOPROXY, GOPRIVATE, GOSUMDB and GONOSUMDB settings of the go command. Downloaded archives are
+verified using the checksum database.`,
 		RunE: command.GenerateRunFunc(c),
 	}
 
@@ -43,19 +61,18 @@ The flags --module-name and --goyave-version are required.`,
 
 // BuildSurvey builds a survey for this action
 func (c *Project) BuildSurvey() ([]*survey.Question, error) {
-	tags, err := git.GetAllTags()
+	source, err := c.TemplateSource()
 	if err != nil {
 		return nil, err
 	}
-
-	versions, err := git.GetVersions(tags)
+	tags, err := source.GetAllTags()
 	if err != nil {
 		return nil, err
 	}
 
-	filteredVersions, err := git.GetTopVersions(versions)
-	if err != nil {
-		return nil, err
+	filteredVersions := git.FilterVersions(tags, c.IncludePrerelease)
+	if len(filteredVersions) == 0 {
+		return nil, errors.New("No version of the template available")
 	}
 
 	return []*survey.Question{
@@ -78,11 +95,6 @@ func (c *Project) BuildSurvey() ([]*survey.Question, error) {
 
 // Execute the command's behavior
 func (c *Project) Execute() error {
-	tags, err := git.GetAllTags()
-	if err != nil {
-		return err
-	}
-
 	projectName := mod.ProjectNameFromModuleName(c.ModuleName)
 
 	info, err := os.Stat(projectName)
@@ -93,18 +105,11 @@ func (c *Project) Execute() error {
 		return err
 	}
 
-	tag, err := git.GetTagByName(c.GoyaveVersion, tags)
+	files, err := c.extractTemplate(projectName)
 	if err != nil {
 		return err
 	}
-
-	if err := git.DownloadFile(tag.ZipballURL, defaultZipFileName); err != nil {
-		return err
-	}
-
-	if _, err := fs.ExtractZip(defaultZipFileName, projectName); err != nil {
-		return err
-	}
+	fmt.Printf("📂 %d files extracted\n", len(files))
 
 	if err := fs.ReplaceAll(projectName, c.ModuleName); err != nil {
 		return err
@@ -114,10 +119,6 @@ func (c *Project) Execute() error {
 		return err
 	}
 
-	if err := os.Remove(defaultZipFileName); err != nil {
-		return err
-	}
-
 	currentWorkingDirectory, err := os.Getwd()
 	if err != nil {
 		return err
@@ -146,8 +147,14 @@ func (c *Project) Execute() error {
 
 // Validate check if required flags are definded
 func (c *Project) Validate() error {
-	if c.GoyaveVersion == "" || c.ModuleName == "" {
-		return errors.New("required flag(s) \"goyave-version\" and \"module-name\" aren't set")
+	if (c.GoyaveVersion == "" && c.Template == "" && c.Ref == "") || c.ModuleName == "" {
+		return errors.New("required flag(s) \"goyave-version\" (or \"template\" or \"ref\") and \"module-name\" aren't set")
+	}
+	if c.Ref != "" && (c.GoyaveVersion != "" || c.Template != "") {
+		return errors.New("the \"ref\" flag cannot be used with the \"goyave-version\" and \"template\" flags")
+	}
+	if c.Template != "" && c.GoyaveVersion != "" {
+		return errors.New("the \"template\" flag cannot be used with the \"goyave-version\" flag")
 	}
 
 	return nil
@@ -166,7 +173,26 @@ func (c *Project) setFlags(flags *pflag.FlagSet) {
 		"goyave-version",
 		"g",
 		"",
-		"The Goyave version used in this project",
+		"The Goyave version used in this project: a version, a constraint (e.g.: \"^4\", \"~4.3\") or \"latest\"",
 	)
-
+	flags.StringVarP(
+		&c.Template,
+		"template",
+		"t",
+		"",
+		"A local template to use instead of the official one: a directory, a .zip or .tar.gz archive or a file:// URL",
+	)
+	flags.StringVar(
+		&c.Ref,
+		"ref",
+		"",
+		"A branch or commit SHA of the template to use instead of a version",
+	)
+	flags.BoolVar(
+		&c.IncludePrerelease,
+		"include-prerelease",
+		false,
+		"Allow the version constraints (e.g.: \"^5\", \"latest\") to select prereleases",
+	)
+	c.SetTemplateSourceFlags(flags)
 }
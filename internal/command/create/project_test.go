@@ -0,0 +1,35 @@
+package create
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		desc    string
+		project *Project
+		valid   bool
+	}{
+		{desc: "version", project: &Project{ModuleName: "example", GoyaveVersion: "v4.0.0"}, valid: true},
+		{desc: "constraint", project: &Project{ModuleName: "example", GoyaveVersion: "^4"}, valid: true},
+		{desc: "template", project: &Project{ModuleName: "example", Template: "template.zip"}, valid: true},
+		{desc: "ref", project: &Project{ModuleName: "example", Ref: "master"}, valid: true},
+		{desc: "missing module name", project: &Project{GoyaveVersion: "v4.0.0"}, valid: false},
+		{desc: "missing version", project: &Project{ModuleName: "example"}, valid: false},
+		{desc: "ref and version", project: &Project{ModuleName: "example", Ref: "master", GoyaveVersion: "v4.0.0"}, valid: false},
+		{desc: "ref and template", project: &Project{ModuleName: "example", Ref: "master", Template: "template.zip"}, valid: false},
+		{desc: "template and version", project: &Project{ModuleName: "example", Template: "template.zip", GoyaveVersion: "latest"}, valid: false},
+	}
+	for _, c := range cases {
+		err := c.project.Validate()
+		if c.valid {
+			assert.Nil(err, c.desc)
+		} else {
+			assert.NotNil(err, c.desc)
+		}
+	}
+}
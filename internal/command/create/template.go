@@ -0,0 +1,127 @@
+package create
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"goyave.dev/gyv/internal/fs"
+	"goyave.dev/gyv/internal/git"
+)
+
+// extractTemplate extracts the project template to the given directory. The template is either
+// the local template given by the user, or the selected version of the template repository,
+// downloaded to the template cache if it isn't already there.
+func (c *Project) extractTemplate(projectName string) ([]string, error) {
+	if c.Template != "" {
+		path, err := localTemplatePath(c.Template)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Println("📦 Using template", path)
+		return extractLocalTemplate(path, projectName)
+	}
+
+	source, err := c.TemplateSource()
+	if err != nil {
+		return nil, err
+	}
+	cache, err := git.NewTemplateCache()
+	if err != nil {
+		return nil, err
+	}
+
+	// Use the cached archive without contacting the API if possible, so creating
+	// a project with a version that was already downloaded works offline.
+	if c.Ref == "" && git.IsExactVersion(c.GoyaveVersion) {
+		version, err := semver.NewVersion(c.GoyaveVersion)
+		if err != nil {
+			return nil, err
+		}
+		if path, ok := cache.Lookup(source.CacheKey(), version.String()); ok {
+			fmt.Println("📦 Using cached template", version.Original())
+			return source.Extract(path, projectName)
+		}
+	}
+
+	tag, err := c.resolveTag(source)
+	if err != nil {
+		return nil, err
+	}
+	version := tag.Name
+	if v, err := semver.NewVersion(tag.Name); err == nil && tag.Name != tag.Commit.SHA {
+		version = v.String()
+	}
+
+	if path, ok := cache.Lookup(source.CacheKey(), version); ok {
+		fmt.Println("📦 Using cached template", tag.Name)
+		return source.Extract(path, projectName)
+	}
+
+	fmt.Println("📦 Downloading template", tag.Name)
+	path, err := cache.Download(source, tag, version)
+	if err != nil {
+		return nil, err
+	}
+	return source.Extract(path, projectName)
+}
+
+// resolveTag returns the version of the template selected by the user: the
+// given branch or commit, or the highest version matching the version query.
+func (c *Project) resolveTag(source git.Source) (*git.Tag, error) {
+	if c.Ref != "" {
+		return source.ResolveRef(c.Ref)
+	}
+	tags, err := source.GetAllTags()
+	if err != nil {
+		return nil, err
+	}
+	return git.ResolveVersion(c.GoyaveVersion, tags, c.IncludePrerelease)
+}
+
+// localTemplatePath returns the path to the local template designated by
+// the given path or "file://" URL.
+func localTemplatePath(template string) (string, error) {
+	path := template
+	if strings.HasPrefix(template, "file://") {
+		u, err := url.Parse(template)
+		if err != nil {
+			return "", fmt.Errorf("Invalid template URL %q: %w", template, err)
+		}
+		path = filepath.FromSlash(u.Path)
+		if u.Host != "" && u.Host != "localhost" {
+			return "", fmt.Errorf("Invalid template URL %q: only local files are supported", template)
+		}
+	} else if strings.Contains(template, "://") {
+		return "", fmt.Errorf("Unsupported template %q: expected a directory, a .zip or .tar.gz archive or a file:// URL", template)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// extractLocalTemplate copies the given template directory to the project directory,
+// or extracts the given ".zip" or ".tar.gz" template archive. The root directory of the
+// archive is removed if it contains one, like the archives of the template repository.
+func extractLocalTemplate(path, projectName string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.ToLower(path)
+	switch {
+	case info.IsDir():
+		return fs.CopyDirectory(path, projectName)
+	case strings.HasSuffix(name, ".zip"):
+		return fs.ExtractZip(path, projectName)
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return fs.ExtractTarGz(path, projectName)
+	}
+	return nil, fmt.Errorf("Unsupported template %q: expected a directory, a .zip or .tar.gz archive or a file:// URL", path)
+}
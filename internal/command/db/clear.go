@@ -1,8 +1,10 @@
 package db
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/cobra"
@@ -13,7 +15,10 @@ import (
 
 // Clear command for clearing database tables.
 type Clear struct {
-	command.ProjectPathCommand
+	command.InjectedCommand
+	LayoutCommand
+	DestructiveCommand
+	Models []string
 }
 
 // BuildCobraCommand builds the cobra command for this action
@@ -21,7 +26,8 @@ func (c *Clear) BuildCobraCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "clear",
 		Short: "Clear database",
-		Long: `Command to delete all records from all registered models.
+		Long: `Command to delete all records from all registered models, or only from the models given with the models flag.
+A typed confirmation is required, or the force flag when not run from a terminal. The command is refused in production unless the force flag is set.
 If project-path is not specified, the nearest directory containing a go.mod file importing Goyave will be used.
 `,
 		RunE: command.GenerateRunFunc(c),
@@ -40,27 +46,31 @@ func (c *Clear) BuildSurvey() ([]*survey.Question, error) {
 // Execute the command's behavior
 func (c *Clear) Execute() error {
 
-	seed, err := inject.DBClear(c.ProjectPath)
-	if err != nil {
+	if err := c.ApplyEnv(os.Stdout); err != nil {
 		return err
 	}
 
-	wd, err := os.Getwd()
-	if err != nil {
-		return err
+	action := "delete all records of all registered models"
+	if len(c.Models) > 0 {
+		action = fmt.Sprintf("delete all records of the models %s", strings.Join(c.Models, ", "))
 	}
-	if err := os.Chdir(c.ProjectPath); err != nil {
+	if err := c.Confirm(&c.InjectedCommand, action); err != nil {
 		return err
 	}
-	fmt.Println("🗑️ Clearing database...")
-	err = seed()
-	if err1 := os.Chdir(wd); err1 != nil && err == nil {
-		err = err1
-	}
+
+	seed, err := inject.DBClear(c.ProjectPath, c.Layout(), c.Models)
 	if err != nil {
 		return err
 	}
 
+	fmt.Println("🗑️ Clearing database...")
+	result := inject.Execute(context.Background(), c.ExecOptions(), func(ctx context.Context) error {
+		return seed(ctx)
+	})
+	if result.Err != nil {
+		return result.Err
+	}
+
 	fmt.Println("✅ Database cleared!")
 
 	return nil
@@ -79,5 +89,13 @@ func (c *Clear) setFlags(flags *pflag.FlagSet) {
 		"",
 		"The path to the Goyave project root",
 	)
-
+	flags.StringSliceVarP(
+		&c.Models,
+		"models",
+		"m",
+		[]string{},
+		"Only clear the records of these models (e.g.: User,Product)",
+	)
+	c.setLayoutFlags(flags)
+	c.setDestructiveFlags(flags)
 }
@@ -16,8 +16,11 @@ func BuildCommand() *cobra.Command {
 
 	commands := []command.Command{
 		&Migrate{},
+		&Rollback{},
+		&MigrateStatus{},
 		&Clear{},
 		&Seed{},
+		&Fresh{},
 	}
 
 	for _, c := range commands {
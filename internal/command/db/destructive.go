@@ -0,0 +1,91 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/pflag"
+	"goyave.dev/gyv/internal/command"
+	"goyave.dev/gyv/internal/config"
+)
+
+const productionEnv = "production"
+
+var (
+	// ErrProduction returned when trying to run a destructive command
+	// in the production environment without the force flag.
+	ErrProduction = errors.New("Refusing to run a destructive command in production. Use --force to run it anyway")
+
+	// ErrNonInteractive returned when trying to run a destructive command
+	// without the force flag while the confirmation cannot be typed.
+	ErrNonInteractive = errors.New("Refusing to run a destructive command without confirmation: stdin is not a terminal. Use --force to run it anyway")
+)
+
+// DestructiveCommand shared composition struct for database commands
+// deleting data. These commands are refused in production unless forced
+// and require a typed confirmation, or the force flag when not run from a terminal.
+type DestructiveCommand struct {
+	Force bool
+}
+
+// Confirm ensures the destructive operation described by "action" (e.g.: "delete all records")
+// can be run against the database of the given command's loaded configuration.
+// "ApplyEnv()" must have been called on the given command first.
+func (c *DestructiveCommand) Confirm(cmd *command.InjectedCommand, action string) error {
+	if c.Force {
+		return nil
+	}
+
+	if isProduction(cmd) {
+		return ErrProduction
+	}
+
+	if !isInteractive() {
+		return ErrNonInteractive
+	}
+
+	expected := cmd.Config.Database().Name
+	if expected == "" {
+		expected = cmd.Env
+	}
+	if expected == "" {
+		expected = config.LocalEnv
+	}
+
+	answer := ""
+	prompt := &survey.Input{
+		Message: fmt.Sprintf("⚠️ This will %s. Type %q to confirm", action, expected),
+	}
+	if err := survey.AskOne(prompt, &answer); err != nil {
+		return err
+	}
+	if answer != expected {
+		return errors.New("Confirmation failed, aborting")
+	}
+	return nil
+}
+
+func (c *DestructiveCommand) setDestructiveFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(
+		&c.Force,
+		"force",
+		false,
+		"Skip the confirmation and allow running in production",
+	)
+}
+
+func isProduction(cmd *command.InjectedCommand) bool {
+	return strings.EqualFold(cmd.Env, productionEnv) ||
+		strings.EqualFold(cmd.Config.GetString("app.environment"), productionEnv)
+}
+
+func isInteractive() bool {
+	stat, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
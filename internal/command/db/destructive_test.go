@@ -0,0 +1,49 @@
+package db
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/gyv/internal/command"
+	"goyave.dev/gyv/internal/config"
+)
+
+func TestConfirm(t *testing.T) {
+	assert := assert.New(t)
+
+	// Simulate a non-interactive run (e.g.: CI, piped input)
+	reader, writer, err := os.Pipe()
+	if !assert.Nil(err) {
+		return
+	}
+	stdin := os.Stdin
+	os.Stdin = reader
+	defer func() {
+		os.Stdin = stdin
+		_ = reader.Close()
+		_ = writer.Close()
+	}()
+
+	productionConfig := config.Config{"app": map[string]interface{}{"environment": "production"}}
+	localConfig := config.Config{"app": map[string]interface{}{"environment": "localhost"}}
+
+	cases := []struct {
+		desc     string
+		cmd      *command.InjectedCommand
+		force    bool
+		expected error
+	}{
+		{desc: "production env", cmd: &command.InjectedCommand{Env: "production", Config: localConfig}, expected: ErrProduction},
+		{desc: "production env case", cmd: &command.InjectedCommand{Env: "Production", Config: config.Config{}}, expected: ErrProduction},
+		{desc: "production config", cmd: &command.InjectedCommand{Config: productionConfig}, expected: ErrProduction},
+		{desc: "non-interactive", cmd: &command.InjectedCommand{Config: localConfig}, expected: ErrNonInteractive},
+		{desc: "non-interactive env", cmd: &command.InjectedCommand{Env: "test", Config: config.Config{}}, expected: ErrNonInteractive},
+		{desc: "forced production", cmd: &command.InjectedCommand{Env: "production", Config: productionConfig}, force: true},
+		{desc: "forced non-interactive", cmd: &command.InjectedCommand{Config: localConfig}, force: true},
+	}
+	for _, c := range cases {
+		destructive := &DestructiveCommand{Force: c.force}
+		assert.Equal(c.expected, destructive.Confirm(c.cmd, "delete all records"), c.desc)
+	}
+}
@@ -0,0 +1,143 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"goyave.dev/gyv/internal/command"
+	"goyave.dev/gyv/internal/inject"
+)
+
+// Fresh command for dropping all tables, migrating and seeding the database.
+type Fresh struct {
+	command.InjectedCommand
+	LayoutCommand
+	DestructiveCommand
+	ExportedFunctions []*inject.SeederFunction
+	Seeders           []string
+}
+
+// BuildCobraCommand builds the cobra command for this action
+func (c *Fresh) BuildCobraCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fresh",
+		Short: "Drop all tables, migrate and seed",
+		Long: `Command to reset the database: the versioned migrations are rolled back if possible, the tables of all
+registered models are dropped, the migrations are run and the given seeders are executed.
+The project is only built once for the whole operation.
+A typed confirmation is required, or the force flag when not run from a terminal. The command is refused in production unless the force flag is set.
+If project-path is not specified, the nearest directory containing a go.mod file importing Goyave will be used.
+`,
+		RunE: command.GenerateRunFunc(c),
+	}
+
+	c.setFlags(cmd.Flags())
+
+	return cmd
+}
+
+// Setup parse AST to find seeder functions.
+func (c *Fresh) Setup() (int, error) {
+	consumedFlags, err := c.InjectedCommand.Setup()
+	if err != nil {
+		return consumedFlags, err
+	}
+
+	seeders, err := inject.FindSeeders(c.ProjectPath, c.ModFile.Module.Mod.Path, c.SeederRoot)
+	if err != nil {
+		return consumedFlags, err
+	}
+
+	c.ExportedFunctions = seeders
+	return consumedFlags, nil
+}
+
+// BuildSurvey builds a survey for this action
+func (c *Fresh) BuildSurvey() ([]*survey.Question, error) {
+	if len(c.ExportedFunctions) == 0 {
+		return []*survey.Question{}, nil
+	}
+
+	defaultOptions := []string{}
+	if s := findSeeder("Run", c.ExportedFunctions, c.SeederRoot); s != nil {
+		defaultOptions = append(defaultOptions, s.String())
+	}
+
+	return []*survey.Question{
+		{
+			Name: "Seeders",
+			Prompt: &survey.MultiSelect{
+				Message: "Select seeders to run",
+				Options: seederNames(c.ExportedFunctions),
+				Default: defaultOptions,
+			},
+		},
+	}, nil
+}
+
+// Execute the command's behavior
+func (c *Fresh) Execute() error {
+
+	seeders, err := findSeeders(c.Seeders, c.ExportedFunctions, c.SeederRoot)
+	if err != nil {
+		return err
+	}
+
+	if err := c.ApplyEnv(os.Stdout); err != nil {
+		return err
+	}
+
+	if err := c.Confirm(&c.InjectedCommand, "drop the tables of all registered models"); err != nil {
+		return err
+	}
+
+	fresh, err := inject.Fresh(c.ProjectPath, c.Layout(), seeders)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("🗑️ Dropping tables, running migrations and seeders...")
+	var applied []string
+	result := inject.Execute(context.Background(), c.ExecOptions(), func(ctx context.Context) (err error) {
+		applied, err = fresh(ctx)
+		return err
+	})
+	for _, name := range applied {
+		fmt.Println("➡️ Applied", name)
+	}
+	if result.Err != nil {
+		return result.Err
+	}
+
+	fmt.Println("✅ Database refreshed!")
+
+	return nil
+}
+
+// Validate checks if required flags are definded
+func (c *Fresh) Validate() error {
+	return nil
+}
+
+func (c *Fresh) setFlags(flags *pflag.FlagSet) {
+	flags.StringSliceVarP(
+		&c.Seeders,
+		"seeders",
+		"s",
+		[]string{},
+		"A list of seeder functions to run after migrating",
+	)
+	flags.StringVarP(
+		&c.ProjectPath,
+		"project-path",
+		"p",
+		"",
+		"The path to the Goyave project root",
+	)
+	c.setLayoutFlags(flags)
+	c.setDestructiveFlags(flags)
+}
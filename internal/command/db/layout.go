@@ -0,0 +1,72 @@
+package db
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/spf13/pflag"
+	"goyave.dev/gyv/internal/inject"
+)
+
+// LayoutCommand shared composition struct for database commands
+// needing to locate the project's models or seeders.
+type LayoutCommand struct {
+	ModelRoot  string
+	SeederRoot string
+}
+
+// Layout returns the database layout matching the command's flags.
+func (c *LayoutCommand) Layout() inject.DatabaseLayout {
+	return inject.DatabaseLayout{
+		ModelRoot:  c.ModelRoot,
+		SeederRoot: c.SeederRoot,
+	}
+}
+
+func (c *LayoutCommand) setLayoutFlags(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&c.ModelRoot,
+		"model-root",
+		inject.DefaultDatabaseLayout.ModelRoot,
+		"The directory containing the models, relative to the project root. Sub-packages are included",
+	)
+	flags.StringVar(
+		&c.SeederRoot,
+		"seeder-root",
+		inject.DefaultDatabaseLayout.SeederRoot,
+		"The directory containing the seeders, relative to the project root. Sub-packages are included",
+	)
+}
+
+// findSeeders returns the seeder functions matching the given names.
+// Names are either qualified by package (e.g.: "seeder/billing.Invoices") or,
+// for the seeders of the root package, unqualified (e.g.: "Run").
+func findSeeders(names []string, seeders []*inject.SeederFunction, root string) ([]*inject.SeederFunction, error) {
+	result := make([]*inject.SeederFunction, 0, len(names))
+	for _, name := range names {
+		seeder := findSeeder(name, seeders, root)
+		if seeder == nil {
+			return nil, fmt.Errorf("Seeder function %q does not exist", name)
+		}
+		result = append(result, seeder)
+	}
+	return result, nil
+}
+
+func findSeeder(name string, seeders []*inject.SeederFunction, root string) *inject.SeederFunction {
+	rootPackage := path.Base(root)
+	for _, s := range seeders {
+		if s.String() == name || (s.Package == rootPackage && s.Function == name) {
+			return s
+		}
+	}
+	return nil
+}
+
+func seederNames(seeders []*inject.SeederFunction) []string {
+	names := make([]string, 0, len(seeders))
+	for _, s := range seeders {
+		names = append(names, s.String())
+	}
+	return names
+}
@@ -1,8 +1,12 @@
 package db
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/cobra"
@@ -13,7 +17,10 @@ import (
 
 // Migrate command for running auto migrations.
 type Migrate struct {
-	command.ProjectPathCommand
+	command.InjectedCommand
+	LayoutCommand
+	DryRun bool
+	Output string
 }
 
 // BuildCobraCommand builds the cobra command for this action
@@ -22,6 +29,10 @@ func (c *Migrate) BuildCobraCommand() *cobra.Command {
 		Use:   "migrate",
 		Short: "Run migrations",
 		Long: `Command to run database migrations.
+Auto-migrations are run first, then the pending versioned migrations from "database/migration" (or the directory
+set by "database.migrationRoot" in gyv.json) are applied in order.
+With the dry-run flag, the database is left untouched and the SQL statements that would be executed are printed
+for each model and pending migration instead, or written to the file named by the output flag.
 If project-path is not specified, the nearest directory containing a go.mod file importing Goyave will be used.
 `,
 		RunE: command.GenerateRunFunc(c),
@@ -39,39 +50,123 @@ func (c *Migrate) BuildSurvey() ([]*survey.Question, error) {
 
 // Execute the command's behavior
 func (c *Migrate) Execute() error {
+	if c.DryRun {
+		return c.executeDryRun()
+	}
 
-	seed, err := inject.Migrate(c.ProjectPath)
+	if err := c.ApplyEnv(os.Stdout); err != nil {
+		return err
+	}
+
+	migrate, err := inject.Migrate(c.ProjectPath, c.Layout())
 	if err != nil {
 		return err
 	}
 
-	wd, err := os.Getwd()
+	fmt.Println("💾 Running migrations...")
+	var applied []string
+	result := inject.Execute(context.Background(), c.ExecOptions(), func(ctx context.Context) (err error) {
+		applied, err = migrate(ctx)
+		return err
+	})
+	for _, name := range applied {
+		fmt.Println("➡️ Applied", name)
+	}
+	if result.Err != nil {
+		return result.Err
+	}
+
+	fmt.Println("✅ Database migrated!")
+
+	return nil
+}
+
+func (c *Migrate) executeDryRun() error {
+	if err := c.ApplyEnv(os.Stdout); err != nil {
+		return err
+	}
+
+	migrateDryRun, err := inject.MigrateDryRun(c.ProjectPath, c.Layout())
 	if err != nil {
 		return err
 	}
-	if err := os.Chdir(c.ProjectPath); err != nil {
+
+	fmt.Println("🔍 Capturing migrations SQL...")
+	var migrations []*inject.MigrationSQL
+	result := inject.Execute(context.Background(), c.ExecOptions(), func(ctx context.Context) (err error) {
+		migrations, err = migrateDryRun(ctx)
 		return err
+	})
+	if result.Err != nil {
+		return result.Err
 	}
-	fmt.Println("💾 Running migrations...")
-	err = seed()
-	if err1 := os.Chdir(wd); err1 != nil && err == nil {
-		err = err1
+
+	if c.Output == "" {
+		fmt.Println()
+		writeMigrationSQL(os.Stdout, migrations)
+		return nil
+	}
+
+	output, err := filepath.Abs(c.Output)
+	if err != nil {
+		return err
 	}
+	file, err := os.Create(output)
 	if err != nil {
 		return err
 	}
+	writeMigrationSQL(file, migrations)
+	if err := file.Close(); err != nil {
+		return err
+	}
 
-	fmt.Println("✅ Database migrated!")
+	fmt.Println("✅ Migrations SQL written to", output)
 
 	return nil
 }
 
+func writeMigrationSQL(w io.Writer, migrations []*inject.MigrationSQL) {
+	for _, m := range migrations {
+		fmt.Fprintf(w, "-- %s\n", m.Name)
+		if len(m.Statements) == 0 {
+			fmt.Fprint(w, "-- Nothing to migrate\n\n")
+			continue
+		}
+		for _, s := range m.Statements {
+			fmt.Fprintf(w, "%s;\n", strings.TrimSuffix(s, ";"))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
 // Validate checks if required flags are definded
 func (c *Migrate) Validate() error {
+	if c.Output != "" && !c.DryRun {
+		return fmt.Errorf("the output flag can only be used with dry-run")
+	}
 	return nil
 }
 
 func (c *Migrate) setFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(
+		&c.DryRun,
+		"dry-run",
+		false,
+		"Print the SQL statements instead of executing them",
+	)
+	flags.BoolVar(
+		&c.DryRun,
+		"pretend",
+		false,
+		"Alias for dry-run",
+	)
+	flags.StringVarP(
+		&c.Output,
+		"output",
+		"o",
+		"",
+		"Write the dry-run SQL statements to this file instead of the standard output",
+	)
 	flags.StringVarP(
 		&c.ProjectPath,
 		"project-path",
@@ -79,5 +174,5 @@ func (c *Migrate) setFlags(flags *pflag.FlagSet) {
 		"",
 		"The path to the Goyave project root",
 	)
-
+	c.setLayoutFlags(flags)
 }
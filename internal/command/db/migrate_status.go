@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"goyave.dev/gyv/internal/command"
+	"goyave.dev/gyv/internal/inject"
+)
+
+// MigrateStatus command for displaying the status of versioned migrations.
+type MigrateStatus struct {
+	command.InjectedCommand
+}
+
+// BuildCobraCommand builds the cobra command for this action
+func (c *MigrateStatus) BuildCobraCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate:status",
+		Short: "Show migrations status",
+		Long: `Command to display which versioned migrations have been applied.
+If project-path is not specified, the nearest directory containing a go.mod file importing Goyave will be used.
+`,
+		RunE: command.GenerateRunFunc(c),
+	}
+
+	c.setFlags(cmd.Flags())
+
+	return cmd
+}
+
+// BuildSurvey builds a survey for this action
+func (c *MigrateStatus) BuildSurvey() ([]*survey.Question, error) {
+	return []*survey.Question{}, nil
+}
+
+// Execute the command's behavior
+func (c *MigrateStatus) Execute() error {
+
+	if err := c.ApplyEnv(os.Stdout); err != nil {
+		return err
+	}
+
+	migrationStatuses, err := inject.MigrationStatuses(c.ProjectPath)
+	if err != nil {
+		return err
+	}
+
+	var statuses []*inject.MigrationStatus
+	result := inject.Execute(context.Background(), c.ExecOptions(), func(ctx context.Context) (err error) {
+		statuses, err = migrationStatuses(ctx)
+		return err
+	})
+	if result.Err != nil {
+		return result.Err
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "STATUS\tMIGRATION\tBATCH\tMIGRATED AT")
+	for _, s := range statuses {
+		if !s.Applied {
+			fmt.Fprintf(writer, "Pending\t%s\t\t\n", s.Name)
+			continue
+		}
+		fmt.Fprintf(writer, "Applied\t%s\t%d\t%s\n", s.Name, s.Batch, s.MigratedAt.Format(time.RFC3339))
+	}
+	return writer.Flush()
+}
+
+// Validate checks if required flags are definded
+func (c *MigrateStatus) Validate() error {
+	return nil
+}
+
+func (c *MigrateStatus) setFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(
+		&c.ProjectPath,
+		"project-path",
+		"p",
+		"",
+		"The path to the Goyave project root",
+	)
+
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/gyv/internal/inject"
+)
+
+func TestWriteMigrationSQL(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		desc       string
+		migrations []*inject.MigrationSQL
+		expected   string
+	}{
+		{desc: "none", migrations: []*inject.MigrationSQL{}, expected: ""},
+		{
+			desc:       "nothing to migrate",
+			migrations: []*inject.MigrationSQL{{Name: "User"}},
+			expected:   "-- User\n-- Nothing to migrate\n\n",
+		},
+		{
+			desc: "statements",
+			migrations: []*inject.MigrationSQL{
+				{Name: "User", Statements: []string{"CREATE TABLE `users` (`id` bigint)", "CREATE INDEX `idx_users_id` ON `users`(`id`);"}},
+				{Name: "Product"},
+				{Name: "20220101120000_add_status_to_orders", Statements: []string{"ALTER TABLE `orders` ADD `status` text"}},
+			},
+			expected: "-- User\n" +
+				"CREATE TABLE `users` (`id` bigint);\n" +
+				"CREATE INDEX `idx_users_id` ON `users`(`id`);\n\n" +
+				"-- Product\n-- Nothing to migrate\n\n" +
+				"-- 20220101120000_add_status_to_orders\n" +
+				"ALTER TABLE `orders` ADD `status` text;\n\n",
+		},
+	}
+	for _, c := range cases {
+		buffer := &bytes.Buffer{}
+		writeMigrationSQL(buffer, c.migrations)
+		assert.Equal(c.expected, buffer.String(), c.desc)
+	}
+}
+
+func TestMigrateValidate(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil((&Migrate{}).Validate())
+	assert.Nil((&Migrate{DryRun: true}).Validate())
+	assert.Nil((&Migrate{DryRun: true, Output: "migrations.sql"}).Validate())
+	assert.NotNil((&Migrate{Output: "migrations.sql"}).Validate())
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"goyave.dev/gyv/internal/command"
+	"goyave.dev/gyv/internal/inject"
+)
+
+// Rollback command for reverting versioned migrations.
+type Rollback struct {
+	command.InjectedCommand
+	DestructiveCommand
+	Steps int
+}
+
+// BuildCobraCommand builds the cobra command for this action
+func (c *Rollback) BuildCobraCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Revert migrations",
+		Long: `Command to revert versioned migrations.
+By default, the last batch of applied migrations is reverted. Use the steps flag to revert
+a specific number of the most recently applied migrations instead.
+A typed confirmation is required, or the force flag when not run from a terminal. The command is refused in production unless the force flag is set.
+If project-path is not specified, the nearest directory containing a go.mod file importing Goyave will be used.
+`,
+		RunE: command.GenerateRunFunc(c),
+	}
+
+	c.setFlags(cmd.Flags())
+
+	return cmd
+}
+
+// BuildSurvey builds a survey for this action
+func (c *Rollback) BuildSurvey() ([]*survey.Question, error) {
+	return []*survey.Question{}, nil
+}
+
+// Execute the command's behavior
+func (c *Rollback) Execute() error {
+
+	if err := c.ApplyEnv(os.Stdout); err != nil {
+		return err
+	}
+
+	if err := c.Confirm(&c.InjectedCommand, "revert migrations"); err != nil {
+		return err
+	}
+
+	rollback, err := inject.Rollback(c.ProjectPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("⏪ Reverting migrations...")
+	var rolledBack []string
+	result := inject.Execute(context.Background(), c.ExecOptions(), func(ctx context.Context) (err error) {
+		rolledBack, err = rollback(ctx, c.Steps)
+		return err
+	})
+	for _, name := range rolledBack {
+		fmt.Println("➡️ Reverted", name)
+	}
+	if result.Err != nil {
+		return result.Err
+	}
+
+	if len(rolledBack) == 0 {
+		fmt.Println("✅ Nothing to revert!")
+		return nil
+	}
+	fmt.Println("✅ Migrations reverted!")
+
+	return nil
+}
+
+// Validate checks if the flags have valid values
+func (c *Rollback) Validate() error {
+	if c.Steps < 0 {
+		return fmt.Errorf("the steps flag must be a positive number")
+	}
+	return nil
+}
+
+func (c *Rollback) setFlags(flags *pflag.FlagSet) {
+	flags.IntVarP(
+		&c.Steps,
+		"steps",
+		"s",
+		0,
+		"The number of migrations to revert (defaults to the last batch)",
+	)
+	flags.StringVarP(
+		&c.ProjectPath,
+		"project-path",
+		"p",
+		"",
+		"The path to the Goyave project root",
+	)
+	c.setDestructiveFlags(flags)
+}
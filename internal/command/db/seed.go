@@ -1,9 +1,9 @@
 package db
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"sort"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/cobra"
@@ -14,8 +14,9 @@ import (
 
 // Seed command for running seeders.
 type Seed struct {
-	command.ProjectPathCommand
-	ExportedFunctions []string
+	command.InjectedCommand
+	LayoutCommand
+	ExportedFunctions []*inject.SeederFunction
 	Seeders           []string
 }
 
@@ -25,6 +26,8 @@ func (c *Seed) BuildCobraCommand() *cobra.Command {
 		Use:   "seed",
 		Short: "Run seeders",
 		Long: `Command to run seeders.
+Seeders are discovered recursively in the seeder root and identified by their package (e.g.: "seeder.Run" or "seeder/billing.Invoices").
+The seeders of the root package can also be identified by their function name only.
 If project-path is not specified, the nearest directory containing a go.mod file importing Goyave will be used.
 `,
 		RunE: command.GenerateRunFunc(c),
@@ -37,35 +40,29 @@ If project-path is not specified, the nearest directory containing a go.mod file
 
 // Setup parse AST to find seeder functions.
 func (c *Seed) Setup() (int, error) {
-	consumedFlags, err := c.ProjectPathCommand.Setup()
+	consumedFlags, err := c.InjectedCommand.Setup()
 	if err != nil {
 		return consumedFlags, err
 	}
 
-	functions, err := inject.FindExportedFunctions(c.ProjectPath + "/database/seeder")
+	seeders, err := inject.FindSeeders(c.ProjectPath, c.ModFile.Module.Mod.Path, c.SeederRoot)
 	if err != nil {
 		return consumedFlags, err
 	}
 
-	if len(functions) == 0 {
+	if len(seeders) == 0 {
 		return consumedFlags, fmt.Errorf("No seeder function found")
 	}
 
-	sort.SliceStable(functions, func(i, j int) bool {
-		return functions[i] == "Run"
-	})
-	c.ExportedFunctions = functions
+	c.ExportedFunctions = seeders
 	return consumedFlags, nil
 }
 
 // BuildSurvey builds a survey for this action
 func (c *Seed) BuildSurvey() ([]*survey.Question, error) {
 	defaultOptions := []string{}
-	for _, v := range c.ExportedFunctions {
-		if v == "Run" {
-			defaultOptions = append(defaultOptions, v)
-			break
-		}
+	if s := findSeeder("Run", c.ExportedFunctions, c.SeederRoot); s != nil {
+		defaultOptions = append(defaultOptions, s.String())
 	}
 
 	return []*survey.Question{
@@ -73,7 +70,7 @@ func (c *Seed) BuildSurvey() ([]*survey.Question, error) {
 			Name: "Seeders",
 			Prompt: &survey.MultiSelect{
 				Message: "Select seeders to run",
-				Options: c.ExportedFunctions,
+				Options: seederNames(c.ExportedFunctions),
 				Default: defaultOptions,
 			},
 			Validate: survey.Required,
@@ -84,31 +81,26 @@ func (c *Seed) BuildSurvey() ([]*survey.Question, error) {
 // Execute the command's behavior
 func (c *Seed) Execute() error {
 
-	for _, s := range c.Seeders {
-		if !functionExists(s, c.ExportedFunctions) {
-			return fmt.Errorf("Seeder function %q does not exist", s)
-		}
-	}
-
-	seed, err := inject.Seeder(c.ProjectPath, c.Seeders)
+	seeders, err := findSeeders(c.Seeders, c.ExportedFunctions, c.SeederRoot)
 	if err != nil {
 		return err
 	}
 
-	wd, err := os.Getwd()
-	if err != nil {
+	if err := c.ApplyEnv(os.Stdout); err != nil {
 		return err
 	}
-	if err := os.Chdir(c.ProjectPath); err != nil {
+
+	seed, err := inject.Seeder(c.ProjectPath, c.Layout(), seeders)
+	if err != nil {
 		return err
 	}
+
 	fmt.Println("💾 Running seeders...")
-	err = seed()
-	if err1 := os.Chdir(wd); err1 != nil && err == nil {
-		err = err1
-	}
-	if err != nil {
-		return err
+	result := inject.Execute(context.Background(), c.ExecOptions(), func(ctx context.Context) error {
+		return seed(ctx)
+	})
+	if result.Err != nil {
+		return result.Err
 	}
 
 	fmt.Println("✅ Database seeded!")
@@ -136,14 +128,5 @@ func (c *Seed) setFlags(flags *pflag.FlagSet) {
 		"",
 		"The path to the Goyave project root",
 	)
-
-}
-
-func functionExists(funcName string, functions []string) bool {
-	for _, f := range functions {
-		if f == funcName {
-			return true
-		}
-	}
-	return false
+	c.setLayoutFlags(flags)
 }
@@ -0,0 +1,122 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"goyave.dev/gyv/internal/config"
+	"goyave.dev/gyv/internal/inject"
+)
+
+var (
+	// Environment the value of the global "env" flag.
+	Environment string
+
+	// Timeout the value of the global "timeout" flag.
+	Timeout time.Duration
+)
+
+// EnvCommand for commands running code depending on the project's configuration.
+// If a command implements "AskEnv()", this function will be called after the
+// survey when the command is run in interactive mode.
+type EnvCommand interface {
+	AskEnv() error
+}
+
+// InjectedCommand shared composition struct for commands executing code
+// injected into a Goyave project. Such commands load the project's configuration,
+// so they let the user select the environment, either with the global "env" flag
+// or interactively.
+// Commands compositing with this one should call "ApplyEnv()" before
+// executing the injected code.
+type InjectedCommand struct {
+	ProjectPathCommand
+	// Config the project's configuration for the selected environment.
+	// Loaded by "ApplyEnv()".
+	Config config.Config
+	Env    string
+}
+
+// Setup ensure the `ProjectPath` field is correctly set (see `ProjectPathCommand.Setup()`)
+// and sets the `Env` field from the global "env" flag. If a timeout is set with the
+// global "timeout" flag, the "auto" backend is replaced by the exec backend, and
+// the plugin backend is refused because a plugin function cannot be stopped.
+func (c *InjectedCommand) Setup() (int, error) {
+	consumedFlags, err := c.ProjectPathCommand.Setup()
+	if err != nil {
+		return consumedFlags, err
+	}
+	if Environment != "" {
+		c.Env = Environment
+	}
+	if Timeout > 0 && inject.DefaultBackend == inject.BackendAuto {
+		// A plugin function cannot be stopped when the timeout is exceeded,
+		// while a subprocess can be killed.
+		inject.DefaultBackend = inject.BackendExec
+	}
+	if Timeout > 0 && inject.DefaultBackend == inject.BackendPlugin {
+		return consumedFlags, fmt.Errorf("the timeout flag cannot be used with the %q backend, use the %q backend", inject.BackendPlugin, inject.BackendExec)
+	}
+	return consumedFlags, nil
+}
+
+// AskEnv prompts the user to select the environment if it hasn't been set
+// already and if there is more than one configuration file in the project.
+func (c *InjectedCommand) AskEnv() error {
+	if c.Env != "" {
+		return nil
+	}
+	environments, err := config.FindEnvironments(c.ProjectPath)
+	if err != nil {
+		return err
+	}
+	if len(environments) < 2 {
+		return nil
+	}
+
+	prompt := &survey.Select{
+		Message: "Environment",
+		Options: environments,
+		Default: environments[0],
+	}
+	return survey.AskOne(prompt, &c.Env)
+}
+
+// ApplyEnv loads the configuration file matching the selected environment
+// (or the "GOYAVE_ENV" environment variable if none was selected) into the `Config`
+// field and writes the configuration and database the command is about to target to
+// the given writer. The selected environment is then passed to the injected code
+// through the options returned by `ExecOptions()`.
+func (c *InjectedCommand) ApplyEnv(w io.Writer) error {
+	env := c.Env
+	if env == "" {
+		env = os.Getenv(config.EnvVariable)
+	}
+	cfg, err := config.Load(c.ProjectPath, env)
+	if err != nil {
+		return err
+	}
+	c.Config = cfg
+	c.Env = env
+	displayEnv := env
+	if displayEnv == "" {
+		displayEnv = config.LocalEnv
+	}
+	fmt.Fprintf(w, "🌍 Environment: %s (%s)\n", displayEnv, config.FileName(env))
+	fmt.Fprintf(w, "🛢️ Database: %s\n", cfg.Database())
+	return nil
+}
+
+// ExecOptions returns the options used to execute the injected code: it runs
+// in the project's directory, with the "GOYAVE_ENV" environment variable set to
+// the selected environment and the timeout set by the global "timeout" flag.
+func (c *InjectedCommand) ExecOptions() inject.ExecOptions {
+	return inject.ExecOptions{
+		Directory: c.ProjectPath,
+		Env:       map[string]string{config.EnvVariable: c.Env},
+		Timeout:   Timeout,
+	}
+}
@@ -0,0 +1,353 @@
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// change a difference between two OpenAPI specifications.
+type change struct {
+	// Operation the method and path of the affected operation (e.g.: "GET /users"),
+	// or the path if the whole path was added or removed.
+	Operation string `json:"operation"`
+
+	// Location the part of the operation affected (e.g.: "request body (application/json) field \"name\"").
+	// Empty if the whole operation is affected.
+	Location string `json:"location,omitempty"`
+
+	Message  string `json:"message"`
+	Breaking bool   `json:"breaking"`
+}
+
+// String returns a readable description of the change.
+func (c *change) String() string {
+	if c.Location == "" {
+		return fmt.Sprintf("%s: %s", c.Operation, c.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", c.Operation, c.Location, c.Message)
+}
+
+// diffReport the changes between two OpenAPI specifications, classified as
+// breaking (existing clients may stop working) or non-breaking.
+type diffReport struct {
+	Breaking    []*change `json:"breaking"`
+	NonBreaking []*change `json:"nonBreaking"`
+}
+
+func (r *diffReport) add(breaking bool, operation, location, format string, args ...interface{}) {
+	c := &change{Operation: operation, Location: location, Message: fmt.Sprintf(format, args...), Breaking: breaking}
+	if breaking {
+		r.Breaking = append(r.Breaking, c)
+	} else {
+		r.NonBreaking = append(r.NonBreaking, c)
+	}
+}
+
+// schemaDirection whether a schema describes data sent by the client or by the server.
+// A change narrowing the accepted requests is breaking, while a change widening
+// the possible responses is breaking.
+type schemaDirection int
+
+const (
+	request schemaDirection = iota
+	response
+)
+
+// differ compares the operations of two decoded OpenAPI specifications.
+type differ struct {
+	old    map[string]interface{}
+	new    map[string]interface{}
+	report *diffReport
+}
+
+// diff returns the changes between the given decoded specifications.
+func diff(old, new map[string]interface{}) *diffReport {
+	d := &differ{old: old, new: new, report: &diffReport{Breaking: []*change{}, NonBreaking: []*change{}}}
+
+	oldPaths, _ := old["paths"].(map[string]interface{})
+	newPaths, _ := new["paths"].(map[string]interface{})
+	for _, path := range sortedKeys(oldPaths, newPaths) {
+		_, inOld := oldPaths[path]
+		_, inNew := newPaths[path]
+		switch {
+		case !inOld:
+			d.report.add(false, path, "", "path added")
+		case !inNew:
+			d.report.add(true, path, "", "path removed")
+		}
+	}
+
+	oldOperations := operations(old)
+	newOperations := operations(new)
+	for _, key := range sortedOperationKeys(oldOperations, newOperations) {
+		oldOperation, inOld := oldOperations[key]
+		newOperation, inNew := newOperations[key]
+		path := key[strings.Index(key, " ")+1:]
+		switch {
+		case !inOld:
+			if _, ok := oldPaths[path]; ok {
+				d.report.add(false, key, "", "operation added")
+			}
+		case !inNew:
+			if _, ok := newPaths[path]; ok {
+				d.report.add(true, key, "", "operation removed")
+			}
+		default:
+			d.compareOperation(key, oldOperation.(map[string]interface{}), newOperation.(map[string]interface{}))
+		}
+	}
+	return d.report
+}
+
+func (d *differ) compareOperation(key string, old, new map[string]interface{}) {
+	oldOperation, _ := old["operation"].(map[string]interface{})
+	newOperation, _ := new["operation"].(map[string]interface{})
+
+	oldParameters := d.parameters(d.old, old["parameters"], oldOperation["parameters"])
+	newParameters := d.parameters(d.new, new["parameters"], newOperation["parameters"])
+	for _, name := range sortedKeys(oldParameters, newParameters) {
+		oldParameter, inOld := oldParameters[name].(map[string]interface{})
+		newParameter, inNew := newParameters[name].(map[string]interface{})
+		location := "parameter " + name
+		switch {
+		case !inOld:
+			if isTrue(newParameter["required"]) {
+				d.report.add(true, key, location, "new required parameter")
+			} else {
+				d.report.add(false, key, location, "new optional parameter")
+			}
+		case !inNew:
+			d.report.add(false, key, location, "parameter removed")
+		default:
+			if !isTrue(oldParameter["required"]) && isTrue(newParameter["required"]) {
+				d.report.add(true, key, location, "parameter became required")
+			}
+			d.compareSchema(key, location, oldParameter["schema"], newParameter["schema"], request, map[string]bool{})
+		}
+	}
+
+	oldBody, _ := d.resolve(d.old, oldOperation["requestBody"]).(map[string]interface{})
+	newBody, _ := d.resolve(d.new, newOperation["requestBody"]).(map[string]interface{})
+	switch {
+	case oldBody == nil && newBody != nil:
+		d.report.add(isTrue(newBody["required"]), key, "request body", "request body added")
+	case oldBody != nil && newBody == nil:
+		d.report.add(false, key, "request body", "request body removed")
+	case oldBody != nil && newBody != nil:
+		if !isTrue(oldBody["required"]) && isTrue(newBody["required"]) {
+			d.report.add(true, key, "request body", "request body became required")
+		}
+		d.compareContent(key, "request body", oldBody["content"], newBody["content"], request)
+	}
+
+	oldResponses, _ := oldOperation["responses"].(map[string]interface{})
+	newResponses, _ := newOperation["responses"].(map[string]interface{})
+	for _, status := range sortedKeys(oldResponses, newResponses) {
+		oldResponse, inOld := d.resolve(d.old, oldResponses[status]).(map[string]interface{})
+		newResponse, inNew := d.resolve(d.new, newResponses[status]).(map[string]interface{})
+		location := "response " + status
+		switch {
+		case !inOld:
+			d.report.add(false, key, location, "response added")
+		case !inNew:
+			d.report.add(true, key, location, "response removed")
+		default:
+			d.compareContent(key, location, oldResponse["content"], newResponse["content"], response)
+		}
+	}
+}
+
+// parameters returns the parameters of an operation, including the ones
+// declared at the path level, identified by their location and name (e.g.: "query.page").
+func (d *differ) parameters(document map[string]interface{}, lists ...interface{}) map[string]interface{} {
+	parameters := map[string]interface{}{}
+	for _, list := range lists {
+		items, _ := list.([]interface{})
+		for _, item := range items {
+			parameter, ok := d.resolve(document, item).(map[string]interface{})
+			if !ok {
+				continue
+			}
+			parameters[fmt.Sprintf("%v.%v", parameter["in"], parameter["name"])] = parameter
+		}
+	}
+	return parameters
+}
+
+func (d *differ) compareContent(key, location string, old, new interface{}, direction schemaDirection) {
+	oldContent, _ := old.(map[string]interface{})
+	newContent, _ := new.(map[string]interface{})
+	for _, mediaType := range sortedKeys(oldContent, newContent) {
+		oldMedia, inOld := oldContent[mediaType].(map[string]interface{})
+		newMedia, inNew := newContent[mediaType].(map[string]interface{})
+		mediaLocation := fmt.Sprintf("%s (%s)", location, mediaType)
+		switch {
+		case !inOld:
+			d.report.add(false, key, mediaLocation, "media type added")
+		case !inNew:
+			d.report.add(true, key, mediaLocation, "media type removed")
+		default:
+			d.compareSchema(key, mediaLocation, oldMedia["schema"], newMedia["schema"], direction, map[string]bool{})
+		}
+	}
+}
+
+// compareSchema compares two schemas recursively. "visited" prevents infinite
+// recursion on recursive schemas.
+func (d *differ) compareSchema(key, location string, oldValue, newValue interface{}, direction schemaDirection, visited map[string]bool) {
+	if ref := refPair(oldValue, newValue); ref != "" {
+		if visited[ref] {
+			return
+		}
+		visited[ref] = true
+		defer delete(visited, ref)
+	}
+	old, _ := d.resolve(d.old, oldValue).(map[string]interface{})
+	new, _ := d.resolve(d.new, newValue).(map[string]interface{})
+	if old == nil || new == nil {
+		if old == nil && new != nil && direction == request {
+			d.report.add(true, key, location, "schema added")
+		}
+		return
+	}
+
+	if oldType, newType := fmt.Sprint(old["type"]), fmt.Sprint(new["type"]); old["type"] != nil && new["type"] != nil && oldType != newType {
+		d.report.add(true, key, location, "type changed from %s to %s", oldType, newType)
+		return
+	}
+
+	d.compareEnum(key, location, old["enum"], new["enum"], direction)
+
+	oldProperties, _ := old["properties"].(map[string]interface{})
+	newProperties, _ := new["properties"].(map[string]interface{})
+	oldRequired := stringSet(old["required"])
+	newRequired := stringSet(new["required"])
+	for _, name := range sortedKeys(oldProperties, newProperties) {
+		_, inOld := oldProperties[name]
+		_, inNew := newProperties[name]
+		fieldLocation := fmt.Sprintf("%s field %q", location, name)
+		switch {
+		case !inOld && direction == request && newRequired[name]:
+			d.report.add(true, key, fieldLocation, "new required field")
+		case !inOld:
+			d.report.add(false, key, fieldLocation, "field added")
+		case !inNew:
+			d.report.add(direction == response, key, fieldLocation, "field removed")
+		default:
+			if direction == request && !oldRequired[name] && newRequired[name] {
+				d.report.add(true, key, fieldLocation, "field became required")
+			}
+			if direction == response && oldRequired[name] && !newRequired[name] {
+				d.report.add(true, key, fieldLocation, "field became optional")
+			}
+			d.compareSchema(key, fieldLocation, oldProperties[name], newProperties[name], direction, visited)
+		}
+	}
+
+	if old["items"] != nil || new["items"] != nil {
+		d.compareSchema(key, location+" items", old["items"], new["items"], direction, visited)
+	}
+}
+
+// compareEnum reports enum values removed from requests (narrowing the accepted values)
+// or added to responses (values clients may not handle) as breaking.
+func (d *differ) compareEnum(key, location string, old, new interface{}, direction schemaDirection) {
+	oldValues, _ := old.([]interface{})
+	newValues, _ := new.([]interface{})
+	if len(oldValues) == 0 && len(newValues) == 0 {
+		return
+	}
+	if len(oldValues) == 0 {
+		d.report.add(direction == request, key, location, "enum added: %s", formatValues(newValues))
+		return
+	}
+	if len(newValues) == 0 {
+		d.report.add(direction == response, key, location, "enum removed")
+		return
+	}
+	removed := difference(oldValues, newValues)
+	added := difference(newValues, oldValues)
+	if len(removed) > 0 {
+		d.report.add(direction == request, key, location, "enum values removed: %s", formatValues(removed))
+	}
+	if len(added) > 0 {
+		d.report.add(direction == response, key, location, "enum values added: %s", formatValues(added))
+	}
+}
+
+// resolve returns the component referenced by the given value if
+// it is a local reference (e.g.: {"$ref": "#/components/schemas/User"}).
+func (d *differ) resolve(document map[string]interface{}, value interface{}) interface{} {
+	for i := 0; i < 10; i++ { // Limited to prevent loops of references
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		ref, ok := object["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return value
+		}
+		var current interface{} = document
+		for _, part := range strings.Split(ref[2:], "/") {
+			part = strings.NewReplacer("~1", "/", "~0", "~").Replace(part)
+			container, _ := current.(map[string]interface{})
+			current = container[part]
+		}
+		value = current
+	}
+	return value
+}
+
+func refPair(old, new interface{}) string {
+	oldObject, _ := old.(map[string]interface{})
+	newObject, _ := new.(map[string]interface{})
+	oldRef, _ := oldObject["$ref"].(string)
+	newRef, _ := newObject["$ref"].(string)
+	if oldRef == "" && newRef == "" {
+		return ""
+	}
+	return oldRef + "|" + newRef
+}
+
+func isTrue(value interface{}) bool {
+	b, ok := value.(bool)
+	return ok && b
+}
+
+func stringSet(value interface{}) map[string]bool {
+	set := map[string]bool{}
+	items, _ := value.([]interface{})
+	for _, item := range items {
+		set[fmt.Sprint(item)] = true
+	}
+	return set
+}
+
+// difference returns the values of "a" that are not in "b".
+func difference(a, b []interface{}) []interface{} {
+	result := []interface{}{}
+	for _, value := range a {
+		found := false
+		for _, other := range b {
+			if reflect.DeepEqual(value, other) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func formatValues(values []interface{}) string {
+	formatted := make([]string, 0, len(values))
+	for _, v := range values {
+		formatted = append(formatted, fmt.Sprintf("%q", fmt.Sprint(v)))
+	}
+	sort.Strings(formatted)
+	return strings.Join(formatted, ", ")
+}
@@ -0,0 +1,211 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const diffOldSpec = `{
+	"openapi": "3.0.0",
+	"paths": {
+		"/users": {
+			"get": {
+				"parameters": [{"name": "page", "in": "query", "schema": {"type": "integer"}}],
+				"responses": {"200": {"content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/User"}}}}}}
+			},
+			"post": {
+				"requestBody": {"content": {"application/json": {"schema": {
+					"type": "object",
+					"required": ["name"],
+					"properties": {
+						"name": {"type": "string"},
+						"role": {"type": "string", "enum": ["admin", "user", "guest"]}
+					}
+				}}}},
+				"responses": {"201": {"description": "Created"}, "422": {"description": "Invalid"}}
+			}
+		},
+		"/articles": {
+			"get": {"responses": {"200": {"description": "OK"}}}
+		}
+	},
+	"components": {"schemas": {"User": {
+		"type": "object",
+		"required": ["id", "email"],
+		"properties": {
+			"id": {"type": "integer"},
+			"email": {"type": "string"},
+			"manager": {"$ref": "#/components/schemas/User"}
+		}
+	}}}
+}`
+
+const diffNewSpec = `{
+	"openapi": "3.0.0",
+	"paths": {
+		"/users": {
+			"get": {
+				"parameters": [
+					{"name": "page", "in": "query", "schema": {"type": "string"}},
+					{"name": "sort", "in": "query", "schema": {"type": "string"}}
+				],
+				"responses": {"200": {"content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/User"}}}}}}
+			},
+			"post": {
+				"requestBody": {"content": {"application/json": {"schema": {
+					"type": "object",
+					"required": ["name", "email"],
+					"properties": {
+						"name": {"type": "string"},
+						"email": {"type": "string"},
+						"role": {"type": "string", "enum": ["admin", "user"]}
+					}
+				}}}},
+				"responses": {"201": {"description": "Created"}}
+			},
+			"delete": {"responses": {"204": {"description": "No content"}}}
+		},
+		"/comments": {
+			"get": {"responses": {"200": {"description": "OK"}}}
+		}
+	},
+	"components": {"schemas": {"User": {
+		"type": "object",
+		"required": ["id"],
+		"properties": {
+			"id": {"type": "integer"},
+			"email": {"type": "string"},
+			"name": {"type": "string"},
+			"manager": {"$ref": "#/components/schemas/User"}
+		}
+	}}}
+}`
+
+// diffYAMLSpec the old specification in YAML, with unquoted response status codes.
+const diffYAMLSpec = `openapi: 3.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: page
+          in: query
+          schema:
+            type: integer
+      responses:
+        200:
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/User"
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                role:
+                  type: string
+                  enum: [admin, user, guest]
+      responses:
+        201:
+          description: Created
+        422:
+          description: Invalid
+  /articles:
+    get:
+      responses:
+        200:
+          description: OK
+components:
+  schemas:
+    User:
+      type: object
+      required: [id, email]
+      properties:
+        id:
+          type: integer
+        email:
+          type: string
+        manager:
+          $ref: "#/components/schemas/User"
+`
+
+func TestDiff(t *testing.T) {
+	assert := assert.New(t)
+	old, err := decode([]byte(diffOldSpec))
+	assert.Nil(err)
+	new, err := decode([]byte(diffNewSpec))
+	assert.Nil(err)
+
+	report := diff(old, new)
+	breaking := []string{}
+	for _, c := range report.Breaking {
+		breaking = append(breaking, c.String())
+	}
+	nonBreaking := []string{}
+	for _, c := range report.NonBreaking {
+		nonBreaking = append(nonBreaking, c.String())
+	}
+	assert.Equal([]string{
+		"/articles: path removed",
+		"GET /users: parameter query.page: type changed from integer to string",
+		"GET /users: response 200 (application/json) items field \"email\": field became optional",
+		"POST /users: request body (application/json) field \"email\": new required field",
+		"POST /users: request body (application/json) field \"role\": enum values removed: \"guest\"",
+		"POST /users: response 422: response removed",
+	}, breaking)
+	assert.Equal([]string{
+		"/comments: path added",
+		"GET /users: parameter query.sort: new optional parameter",
+		"GET /users: response 200 (application/json) items field \"name\": field added",
+		"DELETE /users: operation added",
+	}, nonBreaking)
+
+	same := diff(old, old)
+	assert.Empty(same.Breaking)
+	assert.Empty(same.NonBreaking)
+
+	// YAML with numeric status codes
+	oldYAML, err := decode([]byte(diffYAMLSpec))
+	assert.Nil(err)
+	assert.Equal(old, oldYAML)
+	yamlReport := diff(oldYAML, new)
+	assert.Equal(report, yamlReport)
+}
+
+func TestWriteReport(t *testing.T) {
+	assert := assert.New(t)
+	report := &diffReport{
+		Breaking:    []*change{{Operation: "GET /users", Message: "operation removed", Breaking: true}},
+		NonBreaking: []*change{{Operation: "POST /users", Location: "request body (application/json) field \"name\"", Message: "field added"}},
+	}
+
+	buffer := &bytes.Buffer{}
+	assert.Nil(writeReport(buffer, report, "text"))
+	assert.Equal("💥 Breaking changes (1):\n  • GET /users: operation removed\n➕ Non-breaking changes (1):\n  • POST /users: request body (application/json) field \"name\": field added\n", buffer.String())
+
+	buffer.Reset()
+	assert.Nil(writeReport(buffer, report, "markdown"))
+	assert.Equal("## Breaking changes (1)\n\n- `GET /users`: operation removed\n\n## Non-breaking changes (1)\n\n- `POST /users` request body (application/json) field \"name\": field added\n", buffer.String())
+
+	buffer.Reset()
+	assert.Nil(writeReport(buffer, report, "json"))
+	decoded := &diffReport{}
+	assert.Nil(json.Unmarshal(buffer.Bytes(), decoded))
+	assert.Equal(report, decoded)
+
+	buffer.Reset()
+	assert.Nil(writeReport(buffer, &diffReport{}, "text"))
+	assert.Equal("✅ No changes\n", buffer.String())
+
+	assert.NotNil(writeReport(buffer, report, "xml"))
+}
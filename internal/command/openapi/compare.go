@@ -0,0 +1,174 @@
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// methods the HTTP methods of the operations of an OpenAPI path item, in display order.
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// comparison the differences between two OpenAPI specifications.
+type comparison struct {
+	// Added the operations only present in the new specification (e.g.: "GET /users").
+	Added []string
+	// Removed the operations only present in the old specification.
+	Removed []string
+	// Changed the operations present in both specifications but different.
+	Changed []string
+	// Other the top-level sections other than "paths" that differ (e.g.: "components").
+	Other []string
+}
+
+// Empty returns true if the specifications are equal.
+func (c *comparison) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0 && len(c.Other) == 0
+}
+
+// String returns a readable summary of the differences.
+func (c *comparison) String() string {
+	builder := &strings.Builder{}
+	for _, section := range []struct {
+		title string
+		items []string
+	}{
+		{"Added operations", c.Added},
+		{"Removed operations", c.Removed},
+		{"Changed operations", c.Changed},
+		{"Changed sections", c.Other},
+	} {
+		if len(section.items) == 0 {
+			continue
+		}
+		fmt.Fprintf(builder, "%s:\n", section.title)
+		for _, item := range section.items {
+			fmt.Fprintf(builder, "  • %s\n", item)
+		}
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+// decode parses a JSON or YAML OpenAPI specification into generic values, so
+// specifications can be compared regardless of their format and key order.
+func decode(data []byte) (map[string]interface{}, error) {
+	document := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	return normalizeKeys(document).(map[string]interface{}), nil
+}
+
+// normalizeKeys converts the maps decoded from YAML with non-string keys (e.g.: unquoted
+// response status codes such as "200:") to maps with string keys, like in JSON.
+func normalizeKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			normalized[fmt.Sprint(key)] = normalizeKeys(item)
+		}
+		return normalized
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeKeys(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeKeys(item)
+		}
+		return v
+	}
+	return value
+}
+
+// compare returns the differences between the given decoded specifications.
+func compare(old, new map[string]interface{}) *comparison {
+	result := &comparison{}
+	oldOperations := operations(old)
+	newOperations := operations(new)
+	for _, key := range sortedOperationKeys(oldOperations, newOperations) {
+		oldOperation, inOld := oldOperations[key]
+		newOperation, inNew := newOperations[key]
+		switch {
+		case !inOld:
+			result.Added = append(result.Added, key)
+		case !inNew:
+			result.Removed = append(result.Removed, key)
+		case !reflect.DeepEqual(oldOperation, newOperation):
+			result.Changed = append(result.Changed, key)
+		}
+	}
+
+	for _, key := range sortedKeys(old, new) {
+		if key != "paths" && !reflect.DeepEqual(old[key], new[key]) {
+			result.Other = append(result.Other, key)
+		}
+	}
+	return result
+}
+
+// operations returns the operations of the given specification identified by their
+// method and path (e.g.: "GET /users"). The parameters and servers declared at the
+// path level are included in each of the path's operations.
+func operations(document map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	paths, _ := document["paths"].(map[string]interface{})
+	for path, item := range paths {
+		pathItem, _ := item.(map[string]interface{})
+		for _, method := range methods {
+			operation, ok := pathItem[method]
+			if !ok {
+				continue
+			}
+			result[strings.ToUpper(method)+" "+path] = map[string]interface{}{
+				"operation":  operation,
+				"parameters": pathItem["parameters"],
+				"servers":    pathItem["servers"],
+			}
+		}
+	}
+	return result
+}
+
+// sortedOperationKeys returns the keys of both operation sets,
+// sorted by path then by method.
+func sortedOperationKeys(a, b map[string]interface{}) []string {
+	keys := sortedKeys(a, b)
+	methodIndex := func(key string) int {
+		method := strings.ToLower(key[:strings.Index(key, " ")])
+		for i, m := range methods {
+			if m == method {
+				return i
+			}
+		}
+		return len(methods)
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		pathI := keys[i][strings.Index(keys[i], " ")+1:]
+		pathJ := keys[j][strings.Index(keys[j], " ")+1:]
+		if pathI != pathJ {
+			return pathI < pathJ
+		}
+		return methodIndex(keys[i]) < methodIndex(keys[j])
+	})
+	return keys
+}
+
+func sortedKeys(a, b map[string]interface{}) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,92 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	assert := assert.New(t)
+	old, err := decode([]byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "Shop", "version": "1.0"},
+		"paths": {
+			"/users": {
+				"get": {"responses": {"200": {"description": "OK"}}},
+				"post": {"responses": {"201": {"description": "Created"}}}
+			},
+			"/users/{id}": {
+				"parameters": [{"name": "id", "in": "path", "required": true}],
+				"get": {"responses": {"200": {"description": "OK"}}},
+				"delete": {"responses": {"204": {"description": "No content"}}}
+			}
+		}
+	}`))
+	if !assert.Nil(err) {
+		return
+	}
+
+	// Same specification in YAML with a different key order
+	same, err := decode([]byte(`
+openapi: 3.0.0
+paths:
+  /users/{id}:
+    delete:
+      responses:
+        "204":
+          description: No content
+    get:
+      responses:
+        "200":
+          description: OK
+    parameters:
+      - name: id
+        in: path
+        required: true
+  /users:
+    post:
+      responses:
+        "201":
+          description: Created
+    get:
+      responses:
+        "200":
+          description: OK
+info:
+  version: "1.0"
+  title: Shop
+`))
+	if !assert.Nil(err) {
+		return
+	}
+	result := compare(old, same)
+	assert.True(result.Empty())
+	assert.Empty(result.String())
+
+	new, err := decode([]byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "Shop", "version": "1.1"},
+		"paths": {
+			"/users": {
+				"get": {"responses": {"200": {"description": "OK"}}},
+				"post": {"responses": {"201": {"description": "Created"}, "422": {"description": "Invalid"}}}
+			},
+			"/users/{id}": {
+				"parameters": [{"name": "id", "in": "path", "required": true}],
+				"get": {"responses": {"200": {"description": "OK"}}},
+				"patch": {"responses": {"200": {"description": "OK"}}}
+			}
+		}
+	}`))
+	if !assert.Nil(err) {
+		return
+	}
+	result = compare(old, new)
+	assert.False(result.Empty())
+	assert.Equal([]string{"PATCH /users/{id}"}, result.Added)
+	assert.Equal([]string{"DELETE /users/{id}"}, result.Removed)
+	assert.Equal([]string{"POST /users"}, result.Changed)
+	assert.Equal([]string{"info"}, result.Other)
+	assert.Equal("Added operations:\n  • PATCH /users/{id}\nRemoved operations:\n  • DELETE /users/{id}\nChanged operations:\n  • POST /users\nChanged sections:\n  • info", result.String())
+}
@@ -0,0 +1,221 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"goyave.dev/gyv/internal/command"
+	"goyave.dev/gyv/internal/config"
+)
+
+var diffFormats = []string{"text", "markdown", "json"}
+
+// Diff command comparing two OpenAPI specifications and
+// detecting breaking changes.
+type Diff struct {
+	Old            string
+	New            string
+	Format         string
+	Registrer      string
+	FailOnBreaking bool
+}
+
+// BuildCobraCommand builds the cobra command for this action
+func (c *Diff) BuildCobraCommand() *cobra.Command {
+	run := command.GenerateRunFunc(c)
+	cmd := &cobra.Command{
+		Use:   "diff <old> <new>",
+		Short: "Compare two OpenAPI 3 specifications",
+		Long: `Compare two OpenAPI 3 specifications and classify the changes as breaking or non-breaking:
+removed paths and operations, new required parameters and request fields, narrowed enums, changed response schemas...
+Each side can be a JSON or YAML specification file, or the directory of a Goyave project (e.g.: "." for the
+current project), in which case its specification is generated using the project's gyv.json "openapi" options.`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				c.Old = args[0]
+			}
+			if len(args) > 1 {
+				c.New = args[1]
+			}
+			return run(cmd, args)
+		},
+	}
+
+	c.setFlags(cmd.Flags())
+
+	return cmd
+}
+
+// BuildSurvey builds a survey for this action
+func (c *Diff) BuildSurvey() ([]*survey.Question, error) {
+	questions := []*survey.Question{}
+	if c.Old == "" {
+		questions = append(questions, &survey.Question{
+			Name:     "old",
+			Prompt:   &survey.Input{Message: "Old specification (file or project directory)", Default: "openapi.json"},
+			Validate: survey.Required,
+		})
+	}
+	if c.New == "" {
+		questions = append(questions, &survey.Question{
+			Name:     "new",
+			Prompt:   &survey.Input{Message: "New specification (file or project directory)", Default: "."},
+			Validate: survey.Required,
+		})
+	}
+	return questions, nil
+}
+
+// Execute the command's behavior
+func (c *Diff) Execute() error {
+	old, err := c.load(c.Old)
+	if err != nil {
+		return err
+	}
+	new, err := c.load(c.New)
+	if err != nil {
+		return err
+	}
+
+	report := diff(old, new)
+	if err := writeReport(os.Stdout, report, c.Format); err != nil {
+		return err
+	}
+
+	if c.FailOnBreaking && len(report.Breaking) > 0 {
+		return &command.ExitError{Err: fmt.Errorf("Found %d breaking changes", len(report.Breaking)), Code: 1}
+	}
+	return nil
+}
+
+// load decodes the specification file at the given path, or
+// generates the specification of the project in the given directory.
+func (c *Diff) load(path string) (map[string]interface{}, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		document, err := decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot parse %s: %w", path, err)
+		}
+		return document, nil
+	}
+
+	project := &command.InjectedCommand{ProjectPathCommand: command.ProjectPathCommand{ProjectPath: path}}
+	if _, err := project.Setup(); err != nil {
+		return nil, err
+	}
+	projectConfig, err := config.LoadProject(project.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := project.ApplyEnv(os.Stderr); err != nil {
+		return nil, err
+	}
+	spec, err := generateSpec(project, c.Registrer, projectConfig.OpenAPI)
+	if err != nil {
+		return nil, err
+	}
+	return decode(spec)
+}
+
+// writeReport writes the given report in the given format ("text", "markdown" or "json").
+func writeReport(w io.Writer, report *diffReport, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case "markdown":
+		builder := &strings.Builder{}
+		for _, section := range []struct {
+			title   string
+			changes []*change
+		}{{"Breaking changes", report.Breaking}, {"Non-breaking changes", report.NonBreaking}} {
+			fmt.Fprintf(builder, "## %s (%d)\n\n", section.title, len(section.changes))
+			if len(section.changes) == 0 {
+				builder.WriteString("None.\n\n")
+				continue
+			}
+			for _, c := range section.changes {
+				if c.Location == "" {
+					fmt.Fprintf(builder, "- `%s`: %s\n", c.Operation, c.Message)
+				} else {
+					fmt.Fprintf(builder, "- `%s` %s: %s\n", c.Operation, c.Location, c.Message)
+				}
+			}
+			builder.WriteString("\n")
+		}
+		_, err := io.WriteString(w, strings.TrimSuffix(builder.String(), "\n"))
+		return err
+	case "", "text":
+		if len(report.Breaking) == 0 && len(report.NonBreaking) == 0 {
+			_, err := fmt.Fprintln(w, "✅ No changes")
+			return err
+		}
+		builder := &strings.Builder{}
+		if len(report.Breaking) > 0 {
+			fmt.Fprintf(builder, "💥 Breaking changes (%d):\n", len(report.Breaking))
+			for _, c := range report.Breaking {
+				fmt.Fprintf(builder, "  • %s\n", c)
+			}
+		}
+		if len(report.NonBreaking) > 0 {
+			fmt.Fprintf(builder, "➕ Non-breaking changes (%d):\n", len(report.NonBreaking))
+			for _, c := range report.NonBreaking {
+				fmt.Fprintf(builder, "  • %s\n", c)
+			}
+		}
+		_, err := io.WriteString(w, builder.String())
+		return err
+	}
+	return fmt.Errorf("invalid format %q, must be one of: %s", format, strings.Join(diffFormats, ", "))
+}
+
+// Validate checks if required flags are definded
+func (c *Diff) Validate() error {
+	if c.Old == "" || c.New == "" {
+		return fmt.Errorf("two specifications are required: gyv openapi diff <old> <new>")
+	}
+	for _, f := range append(diffFormats, "") {
+		if c.Format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid format %q, must be one of: %s", c.Format, strings.Join(diffFormats, ", "))
+}
+
+func (c *Diff) setFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(
+		&c.Format,
+		"format",
+		"f",
+		"",
+		"The output format (text, markdown or json)",
+	)
+	flags.BoolVar(
+		&c.FailOnBreaking,
+		"fail-on-breaking",
+		false,
+		"Exit with a non-zero code if breaking changes are found",
+	)
+	flags.StringVar(
+		&c.Registrer,
+		"registrar",
+		"",
+		"The route registrar of the projects to use instead of the detected one (e.g.: http/route.Register)",
+	)
+}
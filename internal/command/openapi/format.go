@@ -0,0 +1,68 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	formatJSON = "json"
+	formatYAML = "yaml"
+)
+
+// inferFormat returns the format of a specification file from its extension.
+// Defaults to JSON.
+func inferFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return formatYAML
+	}
+	return formatJSON
+}
+
+// encode converts the given JSON specification to the given format.
+func encode(spec []byte, format string) ([]byte, error) {
+	switch format {
+	case formatJSON:
+		buffer := &bytes.Buffer{}
+		if err := json.Indent(buffer, spec, "", "  "); err != nil {
+			return nil, err
+		}
+		buffer.WriteByte('\n')
+		return buffer.Bytes(), nil
+	case formatYAML:
+		// JSON being valid YAML, decoding it as a YAML node preserves the order of the keys
+		node := &yaml.Node{}
+		if err := yaml.Unmarshal(spec, node); err != nil {
+			return nil, err
+		}
+		resetStyle(node)
+		buffer := &bytes.Buffer{}
+		encoder := yaml.NewEncoder(buffer)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(node); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+		return buffer.Bytes(), nil
+	}
+	return nil, fmt.Errorf("invalid format %q, must be one of: %s, %s", format, formatJSON, formatYAML)
+}
+
+// resetStyle removes the flow style of the given node and its children so
+// they are written in block style, and only quotes the strings that need it.
+func resetStyle(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode || node.Style&yaml.DoubleQuotedStyle != 0 {
+		node.Style = 0
+	}
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
@@ -0,0 +1,31 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInferFormat(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(formatJSON, inferFormat("openapi.json"))
+	assert.Equal(formatJSON, inferFormat("openapi"))
+	assert.Equal(formatYAML, inferFormat("docs/openapi.yaml"))
+	assert.Equal(formatYAML, inferFormat("/tmp/openapi.YML"))
+}
+
+func TestEncode(t *testing.T) {
+	assert := assert.New(t)
+	spec := []byte(`{"openapi":"3.0.0","info":{"title":"Shop","version":"1.0"},"paths":{"/users":{"get":{"tags":["user"],"responses":{"200":{"description":"OK"}}}}}}`)
+
+	result, err := encode(spec, formatJSON)
+	assert.Nil(err)
+	assert.Equal("{\n  \"openapi\": \"3.0.0\",\n  \"info\": {\n    \"title\": \"Shop\",\n    \"version\": \"1.0\"\n  },\n  \"paths\": {\n    \"/users\": {\n      \"get\": {\n        \"tags\": [\n          \"user\"\n        ],\n        \"responses\": {\n          \"200\": {\n            \"description\": \"OK\"\n          }\n        }\n      }\n    }\n  }\n}\n", string(result))
+
+	result, err = encode(spec, formatYAML)
+	assert.Nil(err)
+	assert.Equal("openapi: 3.0.0\ninfo:\n  title: Shop\n  version: \"1.0\"\npaths:\n  /users:\n    get:\n      tags:\n        - user\n      responses:\n        \"200\":\n          description: OK\n", string(result))
+
+	_, err = encode(spec, "xml")
+	assert.NotNil(err)
+}
@@ -1,20 +1,38 @@
 package openapi
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"goyave.dev/gyv/internal/command"
+	"goyave.dev/gyv/internal/config"
 	"goyave.dev/gyv/internal/inject"
 )
 
 // OpenAPI command implementation for OpenAPI 3 specification generation.
 type OpenAPI struct {
-	command.ProjectPathCommand
-	Output string
+	command.InjectedCommand
+	Output          string
+	Format          string
+	Registrer       string
+	Title           string
+	Version         string
+	Description     string
+	Servers         []string
+	SecuritySchemes []string
+	Check           bool
+
+	// Options the OpenAPI options of the project's "gyv.json"
+	// file, overridden by the flags.
+	Options config.OpenAPI
 }
 
 // BuildCobraCommand builds the cobra command for this action
@@ -23,23 +41,49 @@ func (c *OpenAPI) BuildCobraCommand() *cobra.Command {
 		Use:   "openapi",
 		Short: "Generate an OpenAPI 3 specification",
 		Long: `Generate an OpenAPI 3 specification and saves it to a file named by the output flag.
+Relative output paths are relative to the project root. Use "-" to write the specification to the standard output.
+The format (json or yaml) is inferred from the output's extension if the format flag is not set.
+The output, format and metadata of the specification can also be set in the "openapi" section of the project's gyv.json file.
+The route registrar is detected in the main packages of the project, or can be given with the registrar flag.
+With the check flag, the output file is not written: it is compared with the generated specification and the command
+exits with a non-zero code if they differ, which is useful to detect a stale specification in CI.
 If project-path is not specified, the nearest directory containing a go.mod file importing Goyave will be used.`,
 		RunE: command.GenerateRunFunc(c),
 	}
 
 	c.setFlags(cmd.Flags())
+	cmd.AddCommand((&Diff{}).BuildCobraCommand(), (&Serve{}).BuildCobraCommand())
 
 	return cmd
 }
 
+// Setup loads the OpenAPI options of the project.
+func (c *OpenAPI) Setup() (int, error) {
+	consumedFlags, err := c.InjectedCommand.Setup()
+	if err != nil {
+		return consumedFlags, err
+	}
+
+	project, err := config.LoadProject(c.ProjectPath)
+	if err != nil {
+		return consumedFlags, err
+	}
+	c.Options = project.OpenAPI
+	return consumedFlags, nil
+}
+
 // BuildSurvey builds a survey for this action
 func (c *OpenAPI) BuildSurvey() ([]*survey.Question, error) {
+	defaultOutput := c.Options.Output
+	if defaultOutput == "" {
+		defaultOutput = "openapi.json"
+	}
 	return []*survey.Question{
 		{
 			Name: "output",
 			Prompt: &survey.Input{
 				Message: "File output name",
-				Default: "openapi.json",
+				Default: defaultOutput,
 			},
 			Validate: survey.Required,
 		},
@@ -48,40 +92,180 @@ func (c *OpenAPI) BuildSurvey() ([]*survey.Question, error) {
 
 // Execute the command's behavior
 func (c *OpenAPI) Execute() error {
-	if c.Output == "" {
-		c.Output = "openapi.json"
+	err := c.execute()
+	exitErr := &command.ExitError{}
+	if err != nil && c.Check && !errors.As(err, &exitErr) {
+		// Any failure should fail the CI
+		return &command.ExitError{Err: err, Code: 1}
 	}
+	return err
+}
 
-	generator, err := inject.OpenAPI3Generator(c.ProjectPath)
+func (c *OpenAPI) execute() error {
+	options, err := c.options()
 	if err != nil {
 		return err
 	}
 
-	wd, err := os.Getwd()
-	if err != nil {
+	if c.Check && options.Output == "-" {
+		return fmt.Errorf("the check flag requires an output file")
+	}
+
+	var w io.Writer = os.Stdout
+	if options.Output == "-" {
+		w = os.Stderr // The standard output is reserved for the specification
+	}
+
+	if err := c.ApplyEnv(w); err != nil {
 		return err
 	}
-	if err := os.Chdir(c.ProjectPath); err != nil {
+
+	spec, err := generateSpec(&c.InjectedCommand, c.Registrer, options)
+	if err != nil {
 		return err
 	}
-	spec, err := generator()
-	if err1 := os.Chdir(wd); err1 != nil && err == nil {
-		err = err1
+
+	if c.Check {
+		return c.check(spec, c.outputPath(options.Output), w)
 	}
+
+	spec, err = encode(spec, options.Format)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println("✏ Writing output file")
-	if err := os.WriteFile(fmt.Sprintf("%s%c%s", c.ProjectPath, os.PathSeparator, c.Output), spec, 0644); err != nil {
+	if options.Output == "-" {
+		_, err := os.Stdout.Write(spec)
+		return err
+	}
+
+	fmt.Fprintln(w, "✏ Writing output file")
+	output := c.outputPath(options.Output)
+	if err := os.WriteFile(output, spec, 0644); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, "✅ OpenAPI 3 specification generated!", output)
+
+	return nil
+}
+
+// check compares the generated JSON specification with the given file.
+func (c *OpenAPI) check(spec []byte, path string, w io.Writer) error {
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		return &command.ExitError{Err: fmt.Errorf("Cannot check the OpenAPI specification: %w", err), Code: 1}
+	}
+	old, err := decode(existing)
+	if err != nil {
+		return &command.ExitError{Err: fmt.Errorf("Cannot parse %s: %w", path, err), Code: 1}
+	}
+	new, err := decode(spec)
+	if err != nil {
 		return err
 	}
 
-	fmt.Println("✅ OpenAPI 3 specification generated!")
+	result := compare(old, new)
+	if !result.Empty() {
+		return &command.ExitError{
+			Err:  fmt.Errorf("The OpenAPI specification %s is out of date. Run \"gyv openapi\" to update it.\n%s", path, result),
+			Code: 1,
+		}
+	}
 
+	fmt.Fprintln(w, "✅ OpenAPI 3 specification is up to date!", path)
 	return nil
 }
 
+// generateSpec injects the generator into the project of the given command
+// and returns the JSON specification.
+func generateSpec(project *command.InjectedCommand, registrer string, options config.OpenAPI) ([]byte, error) {
+	generator, err := inject.OpenAPI3Generator(project.ProjectPath, registrer)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec []byte
+	result := inject.Execute(context.Background(), project.ExecOptions(), func(ctx context.Context) (err error) {
+		spec, err = generator(ctx, options)
+		return err
+	})
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	return spec, nil
+}
+
+// options returns the OpenAPI options of the project overridden by the flags,
+// with the default output and the format resolved.
+func (c *OpenAPI) options() (config.OpenAPI, error) {
+	override := config.OpenAPI{
+		Output:      c.Output,
+		Format:      strings.ToLower(c.Format),
+		Title:       c.Title,
+		Version:     c.Version,
+		Description: c.Description,
+	}
+	for _, server := range c.Servers {
+		override.Servers = append(override.Servers, config.OpenAPIServer{URL: server})
+	}
+	for _, s := range c.SecuritySchemes {
+		name, scheme, err := parseSecurityScheme(s)
+		if err != nil {
+			return config.OpenAPI{}, err
+		}
+		if override.SecuritySchemes == nil {
+			override.SecuritySchemes = map[string]interface{}{}
+		}
+		override.SecuritySchemes[name] = scheme
+		override.Security = append(override.Security, map[string][]string{name: {}})
+	}
+
+	options := c.Options.Merge(override)
+	if options.Output == "" {
+		options.Output = "openapi.json"
+		if options.Format == formatYAML {
+			options.Output = "openapi.yaml"
+		}
+	}
+	if options.Format == "" {
+		options.Format = inferFormat(options.Output)
+	}
+	if options.Format != formatJSON && options.Format != formatYAML {
+		return config.OpenAPI{}, fmt.Errorf("invalid format %q, must be one of: %s, %s", options.Format, formatJSON, formatYAML)
+	}
+	return options, nil
+}
+
+// outputPath returns the path to the output file. Relative
+// paths are relative to the project root.
+func (c *OpenAPI) outputPath(output string) string {
+	if filepath.IsAbs(output) {
+		return output
+	}
+	return filepath.Join(c.ProjectPath, output)
+}
+
+// parseSecurityScheme parses a security scheme given in the form "name=type" with
+// type being "bearer", "basic" or "apiKey:<in>:<parameter>" (e.g.: "apiKey:header:X-API-Key").
+func parseSecurityScheme(value string) (string, map[string]interface{}, error) {
+	i := strings.Index(value, "=")
+	if i <= 0 {
+		return "", nil, fmt.Errorf("invalid security scheme %q, expected \"<name>=<bearer|basic|apiKey:<in>:<parameter>>\"", value)
+	}
+	name, kind := value[:i], value[i+1:]
+	switch {
+	case strings.EqualFold(kind, "bearer"), strings.EqualFold(kind, "basic"):
+		return name, map[string]interface{}{"type": "http", "scheme": strings.ToLower(kind)}, nil
+	case strings.HasPrefix(kind, "apiKey:"):
+		parts := strings.Split(kind, ":")
+		if len(parts) == 3 && (parts[1] == "header" || parts[1] == "query" || parts[1] == "cookie") && parts[2] != "" {
+			return name, map[string]interface{}{"type": "apiKey", "in": parts[1], "name": parts[2]}, nil
+		}
+	}
+	return "", nil, fmt.Errorf("invalid security scheme %q, expected \"<name>=<bearer|basic|apiKey:<in>:<parameter>>\"", value)
+}
+
 // Validate is a function which check if required flags are definded
 func (c *OpenAPI) Validate() error {
 	return nil
@@ -93,7 +277,56 @@ func (c *OpenAPI) setFlags(flags *pflag.FlagSet) {
 		"output",
 		"o",
 		"",
-		"File output name",
+		"File output name, \"-\" for the standard output",
+	)
+	flags.StringVarP(
+		&c.Format,
+		"format",
+		"f",
+		"",
+		"The output format (json or yaml)",
+	)
+	flags.StringVar(
+		&c.Title,
+		"title",
+		"",
+		"The title of the API",
+	)
+	flags.StringVar(
+		&c.Version,
+		"api-version",
+		"",
+		"The version of the API",
+	)
+	flags.StringVar(
+		&c.Description,
+		"description",
+		"",
+		"The description of the API",
+	)
+	flags.StringSliceVar(
+		&c.Servers,
+		"server",
+		[]string{},
+		"The URLs of the servers of the API (e.g.: https://api.example.org)",
+	)
+	flags.StringArrayVar(
+		&c.SecuritySchemes,
+		"security-scheme",
+		[]string{},
+		"A security scheme required by all operations (e.g.: bearerAuth=bearer, key=apiKey:header:X-API-Key)",
+	)
+	flags.BoolVar(
+		&c.Check,
+		"check",
+		false,
+		"Compare the output file with the generated specification instead of writing it, exit with a non-zero code if they differ",
+	)
+	flags.StringVar(
+		&c.Registrer,
+		"registrar",
+		"",
+		"The route registrar to use instead of the detected one (e.g.: http/route.Register)",
 	)
 	flags.StringVarP(
 		&c.ProjectPath,
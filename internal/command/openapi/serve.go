@@ -0,0 +1,227 @@
+package openapi
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"goyave.dev/gyv/internal/command"
+	"goyave.dev/gyv/internal/config"
+	"goyave.dev/gyv/internal/inject"
+)
+
+//go:embed ui
+var uiFolder embed.FS
+
+var indexTemplate = template.Must(template.ParseFS(uiFolder, "ui/index.html"))
+
+// Serve command serving the OpenAPI specification of the project
+// with an embedded Swagger UI.
+type Serve struct {
+	command.InjectedCommand
+	Host      string
+	Port      int
+	Registrer string
+	Interval  time.Duration
+	NoWatch   bool
+}
+
+// BuildCobraCommand builds the cobra command for this action
+func (c *Serve) BuildCobraCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the OpenAPI 3 specification with Swagger UI",
+		Long: `Generate the OpenAPI 3 specification of the project and serve it with an embedded Swagger UI, working offline.
+The specification is available at "/openapi.json". It is regenerated when the project's Go files or configuration change,
+and opened pages are refreshed automatically. The metadata of the specification is read from the "openapi" section of gyv.json.
+If project-path is not specified, the nearest directory containing a go.mod file importing Goyave will be used.`,
+		RunE: command.GenerateRunFunc(c),
+	}
+
+	c.setFlags(cmd.Flags())
+
+	return cmd
+}
+
+// BuildSurvey builds a survey for this action
+func (c *Serve) BuildSurvey() ([]*survey.Question, error) {
+	return []*survey.Question{}, nil
+}
+
+// Execute the command's behavior
+func (c *Serve) Execute() error {
+	if err := c.ApplyEnv(os.Stdout); err != nil {
+		return err
+	}
+
+	project, err := config.LoadProject(c.ProjectPath)
+	if err != nil {
+		return err
+	}
+
+	if !c.NoWatch {
+		// A modified plugin cannot be loaded again in the same process
+		switch inject.DefaultBackend {
+		case inject.BackendAuto:
+			inject.DefaultBackend = inject.BackendExec
+		case inject.BackendPlugin:
+			return fmt.Errorf("the %q backend cannot regenerate the specification, use the %q backend or --no-watch", inject.BackendPlugin, inject.BackendExec)
+		}
+	}
+
+	server := &specServer{title: project.OpenAPI.Title}
+	generate := func() error {
+		spec, err := generateSpec(&c.InjectedCommand, c.Registrer, project.OpenAPI)
+		if err != nil {
+			return err
+		}
+		server.update(spec)
+		return nil
+	}
+
+	// Fingerprint before generating so changes made during the generation are detected
+	lastFingerprint, err := fingerprint(c.ProjectPath)
+	if err != nil {
+		return err
+	}
+	if err := generate(); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(c.Host, strconv.Itoa(c.Port)))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("📖 Serving the OpenAPI specification on http://%s\n", listener.Addr())
+
+	if !c.NoWatch {
+		// Each change would add an entry to the plugin cache that is
+		// never reused, the regenerations are built without it.
+		inject.NoCache = true
+		go func() {
+			for range time.Tick(c.Interval) {
+				current, err := fingerprint(c.ProjectPath)
+				if err != nil || current == lastFingerprint {
+					continue
+				}
+				lastFingerprint = current
+				fmt.Println("🔄 Changes detected, regenerating the specification")
+				if err := generate(); err != nil {
+					fmt.Fprintf(os.Stderr, "❌ %s\n", err.Error())
+					continue
+				}
+				fmt.Println("✅ OpenAPI 3 specification regenerated!")
+			}
+		}()
+	}
+
+	return http.Serve(listener, server.handler())
+}
+
+// specServer serves the last generated specification and Swagger UI.
+type specServer struct {
+	mu       sync.RWMutex
+	spec     []byte
+	revision int
+	title    string
+}
+
+func (s *specServer) update(spec []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.spec = spec
+	s.revision++
+}
+
+func (s *specServer) handler() http.Handler {
+	assets, err := fs.Sub(uiFolder, "ui/swagger-ui")
+	if err != nil {
+		panic(err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/swagger-ui/", http.StripPrefix("/swagger-ui/", http.FileServer(http.FS(assets))))
+	mux.HandleFunc("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = w.Write(s.spec)
+	})
+	mux.HandleFunc("/revision", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		w.Header().Set("Cache-Control", "no-store")
+		fmt.Fprint(w, s.revision)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		title := s.title
+		if title == "" {
+			title = "OpenAPI specification"
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = indexTemplate.Execute(w, map[string]string{"Title": title})
+	})
+	return mux
+}
+
+// Validate checks if required flags are definded
+func (c *Serve) Validate() error {
+	if c.Interval <= 0 {
+		return fmt.Errorf("the interval must be positive")
+	}
+	return nil
+}
+
+func (c *Serve) setFlags(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&c.Host,
+		"host",
+		"localhost",
+		"The host the documentation server listens on",
+	)
+	flags.IntVar(
+		&c.Port,
+		"port",
+		8090,
+		"The port the documentation server listens on",
+	)
+	flags.DurationVar(
+		&c.Interval,
+		"interval",
+		time.Second,
+		"How often the project's files are checked for changes",
+	)
+	flags.BoolVar(
+		&c.NoWatch,
+		"no-watch",
+		false,
+		"Don't regenerate the specification when the project's files change",
+	)
+	flags.StringVar(
+		&c.Registrer,
+		"registrar",
+		"",
+		"The route registrar to use instead of the detected one (e.g.: http/route.Register)",
+	)
+	flags.StringVarP(
+		&c.ProjectPath,
+		"project-path",
+		"p",
+		"",
+		"The path to the Goyave project root",
+	)
+}
@@ -0,0 +1,92 @@
+package openapi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpecServer(t *testing.T) {
+	assert := assert.New(t)
+	server := &specServer{title: "Shop API"}
+	server.update([]byte(`{"openapi":"3.0.0"}`))
+	handler := server.handler()
+
+	get := func(path string) (*http.Response, string) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		response := recorder.Result()
+		body, _ := io.ReadAll(response.Body)
+		return response, string(body)
+	}
+
+	response, body := get("/")
+	assert.Equal(http.StatusOK, response.StatusCode)
+	assert.Contains(body, "<title>Shop API</title>")
+	assert.Contains(body, "swagger-ui/swagger-ui-bundle.js")
+
+	response, body = get("/openapi.json")
+	assert.Equal(http.StatusOK, response.StatusCode)
+	assert.Equal("application/json", response.Header.Get("Content-Type"))
+	assert.Equal(`{"openapi":"3.0.0"}`, body)
+
+	_, body = get("/revision")
+	assert.Equal("1", body)
+	server.update([]byte(`{"openapi":"3.0.1"}`))
+	_, body = get("/revision")
+	assert.Equal("2", body)
+
+	response, _ = get("/swagger-ui/swagger-ui-bundle.js")
+	assert.Equal(http.StatusOK, response.StatusCode)
+	response, _ = get("/swagger-ui/swagger-ui.css")
+	assert.Equal(http.StatusOK, response.StatusCode)
+
+	response, _ = get("/missing")
+	assert.Equal(http.StatusNotFound, response.StatusCode)
+}
+
+func TestFingerprint(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0744); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("main.go", "package main\n")
+	write("config.json", "{}")
+
+	initial, err := fingerprint(dir)
+	assert.Nil(err)
+
+	// Ignored files
+	write("README.md", "readme")
+	write(".git/HEAD", "ref")
+	write("vendor/example/lib.go", "package lib\n")
+	write("resources/data.json", "{}")
+	current, err := fingerprint(dir)
+	assert.Nil(err)
+	assert.Equal(initial, current)
+
+	write("http/route/route.go", "package route\n")
+	current, err = fingerprint(dir)
+	assert.Nil(err)
+	assert.NotEqual(initial, current)
+
+	initial = current
+	later := time.Now().Add(time.Minute)
+	assert.Nil(os.Chtimes(filepath.Join(dir, "config.json"), later, later))
+	current, err = fingerprint(dir)
+	assert.Nil(err)
+	assert.NotEqual(initial, current)
+}
@@ -0,0 +1,44 @@
+package openapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// fingerprint returns a hash of the name, size and modification time of the
+// files of the given project that can affect its OpenAPI specification: Go
+// source files, "go.mod", "go.sum" and the JSON configuration files at the root.
+// Hidden directories, "vendor" and "node_modules" are ignored.
+func fingerprint(projectPath string) (string, error) {
+	hash := sha256.New()
+	err := filepath.WalkDir(projectPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != projectPath && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rootJSON := filepath.Dir(path) == filepath.Clean(projectPath) && filepath.Ext(name) == ".json"
+		if filepath.Ext(name) != ".go" && name != "go.mod" && name != "go.sum" && !rootJSON {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(hash, "%s|%d|%d\n", path, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,26 @@
+package route
+
+import (
+	"goyave.dev/gyv/internal/command"
+
+	"github.com/spf13/cobra"
+)
+
+// BuildCommand builds a parent command for all route-related subcommands
+func BuildCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "route",
+		Short: "Routing operations",
+		Long:  "Command for routing operations, such as listing the registered routes.",
+	}
+
+	commands := []command.Command{
+		&List{},
+	}
+
+	for _, c := range commands {
+		cmd.AddCommand(c.BuildCobraCommand())
+	}
+
+	return cmd
+}
@@ -0,0 +1,217 @@
+package route
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"goyave.dev/gyv/internal/command"
+	"goyave.dev/gyv/internal/inject"
+)
+
+var formats = []string{"table", "json", "csv"}
+
+// List command for listing the routes registered in the application's router.
+type List struct {
+	command.InjectedCommand
+	Format     string
+	Method     string
+	NamePrefix string
+	PathPrefix string
+	Registrer  string
+}
+
+// BuildCobraCommand builds the cobra command for this action
+func (c *List) BuildCobraCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List registered routes",
+		Long: `Command to list the routes registered in the application's main router.
+For each route, the methods, URI, name, handler and middleware chain are displayed.
+The route registrar is detected in the main packages of the project, or can be given with the registrar flag.
+If project-path is not specified, the nearest directory containing a go.mod file importing Goyave will be used.`,
+		RunE: command.GenerateRunFunc(c),
+	}
+
+	c.setFlags(cmd.Flags())
+
+	return cmd
+}
+
+// BuildSurvey builds a survey for this action
+func (c *List) BuildSurvey() ([]*survey.Question, error) {
+	return []*survey.Question{
+		{
+			Name: "Format",
+			Prompt: &survey.Select{
+				Message: "Output format",
+				Options: formats,
+				Default: formats[0],
+			},
+		},
+	}, nil
+}
+
+// Execute the command's behavior
+func (c *List) Execute() error {
+	if c.Format == "" {
+		c.Format = formats[0]
+	}
+
+	if err := c.ApplyEnv(os.Stderr); err != nil {
+		return err
+	}
+
+	listRoutes, err := inject.RouteList(c.ProjectPath, c.Registrer)
+	if err != nil {
+		return err
+	}
+
+	var routes []*inject.Route
+	result := inject.Execute(context.Background(), c.ExecOptions(), func(ctx context.Context) (err error) {
+		routes, err = listRoutes(ctx)
+		return err
+	})
+	if result.Err != nil {
+		return result.Err
+	}
+
+	routes = c.filter(routes)
+
+	switch c.Format {
+	case "json":
+		return writeJSON(os.Stdout, routes)
+	case "csv":
+		return writeCSV(os.Stdout, routes)
+	default:
+		return writeTable(os.Stdout, routes)
+	}
+}
+
+func (c *List) filter(routes []*inject.Route) []*inject.Route {
+	filtered := make([]*inject.Route, 0, len(routes))
+	for _, r := range routes {
+		if c.Method != "" && !hasMethod(r, c.Method) {
+			continue
+		}
+		if !strings.HasPrefix(r.Name, c.NamePrefix) || !strings.HasPrefix(r.URI, c.PathPrefix) {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+	return filtered
+}
+
+func hasMethod(route *inject.Route, method string) bool {
+	for _, m := range route.Methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeTable(w io.Writer, routes []*inject.Route) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "METHOD\tURI\tNAME\tHANDLER\tMIDDLEWARE")
+	for _, r := range routes {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+			strings.Join(r.Methods, "|"),
+			r.URI,
+			r.Name,
+			r.Handler,
+			strings.Join(r.Middleware, ", "),
+		)
+	}
+	return writer.Flush()
+}
+
+func writeJSON(w io.Writer, routes []*inject.Route) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(routes)
+}
+
+func writeCSV(w io.Writer, routes []*inject.Route) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"method", "uri", "name", "handler", "middleware"}); err != nil {
+		return err
+	}
+	for _, r := range routes {
+		record := []string{
+			strings.Join(r.Methods, "|"),
+			r.URI,
+			r.Name,
+			r.Handler,
+			strings.Join(r.Middleware, "|"),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Validate checks if the flags have valid values
+func (c *List) Validate() error {
+	if c.Format == "" {
+		return nil
+	}
+	for _, f := range formats {
+		if c.Format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid format %q, must be one of: %s", c.Format, strings.Join(formats, ", "))
+}
+
+func (c *List) setFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(
+		&c.Format,
+		"format",
+		"f",
+		"",
+		"The output format (table, json or csv)",
+	)
+	flags.StringVarP(
+		&c.Method,
+		"method",
+		"m",
+		"",
+		"Only list the routes matching this HTTP method",
+	)
+	flags.StringVar(
+		&c.NamePrefix,
+		"name",
+		"",
+		"Only list the routes whose name starts with this prefix",
+	)
+	flags.StringVar(
+		&c.PathPrefix,
+		"path",
+		"",
+		"Only list the routes whose URI starts with this prefix",
+	)
+	flags.StringVar(
+		&c.Registrer,
+		"registrar",
+		"",
+		"The route registrar to use instead of the detected one (e.g.: http/route.Register)",
+	)
+	flags.StringVarP(
+		&c.ProjectPath,
+		"project-path",
+		"p",
+		"",
+		"The path to the Goyave project root",
+	)
+}
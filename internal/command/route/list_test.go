@@ -0,0 +1,97 @@
+package route
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/gyv/internal/inject"
+)
+
+func testRoutes() []*inject.Route {
+	return []*inject.Route{
+		{Methods: []string{"GET", "HEAD"}, URI: "/users", Name: "user.index", Handler: "user.Index", Middleware: []string{"auth.Middleware"}},
+		{Methods: []string{"POST"}, URI: "/users", Name: "user.store", Handler: "user.Store", Middleware: []string{"auth.Middleware", "validation.Middleware"}},
+		{Methods: []string{"GET", "HEAD"}, URI: "/articles/{id}", Name: "article.show", Handler: "article.Show"},
+		{Methods: []string{"DELETE"}, URI: "/articles/{id}", Name: "", Handler: "article.Destroy"},
+	}
+}
+
+func TestFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		desc     string
+		list     *List
+		expected []string
+	}{
+		{desc: "no filter", list: &List{}, expected: []string{"user.Index", "user.Store", "article.Show", "article.Destroy"}},
+		{desc: "method", list: &List{Method: "GET"}, expected: []string{"user.Index", "article.Show"}},
+		{desc: "method case", list: &List{Method: "delete"}, expected: []string{"article.Destroy"}},
+		{desc: "unknown method", list: &List{Method: "PATCH"}, expected: []string{}},
+		{desc: "name prefix", list: &List{NamePrefix: "user."}, expected: []string{"user.Index", "user.Store"}},
+		{desc: "path prefix", list: &List{PathPrefix: "/articles"}, expected: []string{"article.Show", "article.Destroy"}},
+		{desc: "combined", list: &List{Method: "get", PathPrefix: "/users", NamePrefix: "user"}, expected: []string{"user.Index"}},
+		{desc: "combined no match", list: &List{Method: "POST", PathPrefix: "/articles"}, expected: []string{}},
+	}
+	for _, c := range cases {
+		handlers := []string{}
+		for _, r := range c.list.filter(testRoutes()) {
+			handlers = append(handlers, r.Handler)
+		}
+		assert.Equal(c.expected, handlers, c.desc)
+	}
+}
+
+func TestWriteRoutes(t *testing.T) {
+	assert := assert.New(t)
+	routes := testRoutes()[:2]
+
+	cases := []struct {
+		write    func(w io.Writer, routes []*inject.Route) error
+		desc     string
+		routes   []*inject.Route
+		expected string
+	}{
+		{
+			desc:   "table",
+			write:  writeTable,
+			routes: routes,
+			expected: "METHOD    URI     NAME        HANDLER     MIDDLEWARE\n" +
+				"GET|HEAD  /users  user.index  user.Index  auth.Middleware\n" +
+				"POST      /users  user.store  user.Store  auth.Middleware, validation.Middleware\n",
+		},
+		{
+			desc:   "csv",
+			write:  writeCSV,
+			routes: routes,
+			expected: "method,uri,name,handler,middleware\n" +
+				"GET|HEAD,/users,user.index,user.Index,auth.Middleware\n" +
+				"POST,/users,user.store,user.Store,auth.Middleware|validation.Middleware\n",
+		},
+		{desc: "empty table", write: writeTable, expected: "METHOD  URI  NAME  HANDLER  MIDDLEWARE\n"},
+		{desc: "empty csv", write: writeCSV, expected: "method,uri,name,handler,middleware\n"},
+	}
+	for _, c := range cases {
+		buffer := &bytes.Buffer{}
+		if assert.Nil(c.write(buffer, c.routes), c.desc) {
+			assert.Equal(c.expected, buffer.String(), c.desc)
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	assert.Nil(writeJSON(buffer, routes))
+	decoded := []*inject.Route{}
+	assert.Nil(json.Unmarshal(buffer.Bytes(), &decoded))
+	assert.Equal(routes, decoded)
+}
+
+func TestListValidate(t *testing.T) {
+	assert := assert.New(t)
+	for _, format := range append(formats, "") {
+		assert.Nil((&List{Format: format}).Validate(), format)
+	}
+	assert.NotNil((&List{Format: "xml"}).Validate())
+}
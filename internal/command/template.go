@@ -0,0 +1,62 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"goyave.dev/gyv/internal/git"
+)
+
+// TemplateSourceCommand shared composition struct for commands using the
+// versions of a project template. The template versions are either retrieved
+// from a template repository or, through the Go module proxy, from a template module.
+type TemplateSourceCommand struct {
+	// TemplateRepository the full name of the template repository (e.g.: "go-goyave/template").
+	TemplateRepository string
+	// APIURL the base URL of the API of the server hosting the template repository.
+	APIURL string
+	// Source where the template versions are listed and downloaded from ("github" or "proxy").
+	Source string
+	// TemplateModule the module path of the template when using the "proxy" source.
+	TemplateModule string
+}
+
+// TemplateSource returns the source of the template versions selected by the user:
+// the template repository or the template module through the Go module proxy.
+func (c *TemplateSourceCommand) TemplateSource() (git.Source, error) {
+	switch c.Source {
+	case git.SourceGitHub, "":
+		return git.NewRepository(c.TemplateRepository, c.APIURL)
+	case git.SourceProxy:
+		return git.NewModuleProxy(c.TemplateModule)
+	}
+	return nil, fmt.Errorf("Invalid source %q, must be one of: %s, %s", c.Source, git.SourceGitHub, git.SourceProxy)
+}
+
+// SetTemplateSourceFlags adds the flags selecting the template source to the given flag set.
+func (c *TemplateSourceCommand) SetTemplateSourceFlags(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&c.TemplateRepository,
+		"template-repo",
+		git.DefaultTemplateRepository,
+		"The template repository (owner/name)",
+	)
+	flags.StringVar(
+		&c.APIURL,
+		"api-url",
+		git.DefaultAPIURL,
+		"The base URL of the API of the server hosting the template repository (e.g.: https://github.example.org/api/v3)",
+	)
+	flags.StringVar(
+		&c.Source,
+		"source",
+		git.SourceGitHub,
+		"Where the template versions are downloaded from: \"github\" (the template repository) or \"proxy\" (the Go module proxy)",
+	)
+	flags.StringVar(
+		&c.TemplateModule,
+		"template-module",
+		git.DefaultTemplateModule,
+		"The module path of the template, used with the \"proxy\" source",
+	)
+}
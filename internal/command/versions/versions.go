@@ -0,0 +1,141 @@
+package versions
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"goyave.dev/gyv/internal/command"
+	"goyave.dev/gyv/internal/git"
+)
+
+var formats = []string{"text", "json"}
+
+// Versions command listing the available versions of the project template.
+type Versions struct {
+	command.TemplateSourceCommand
+	Constraint        string
+	IncludePrerelease bool
+	Top               bool
+	Format            string
+}
+
+// BuildCobraCommand builds the cobra command for this action
+func (c *Versions) BuildCobraCommand() *cobra.Command {
+	run := command.GenerateRunFunc(c)
+	cmd := &cobra.Command{
+		Use:   "versions [constraint]",
+		Short: "List the available versions of the project template",
+		Long: `List the versions of the project template that can be used with "gyv create project", from the highest to the lowest.
+The versions can be filtered with a constraint (e.g.: "^4", "~4.3", ">= 4.2, < 4.5"). Prereleases are only listed with --include-prerelease.
+The template source is selected with the same flags as "gyv create project".`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				c.Constraint = args[0]
+			}
+			return run(cmd, args)
+		},
+	}
+
+	c.setFlags(cmd.Flags())
+
+	return cmd
+}
+
+// BuildSurvey builds a survey for this action
+func (c *Versions) BuildSurvey() ([]*survey.Question, error) {
+	return []*survey.Question{}, nil
+}
+
+// Execute the command's behavior
+func (c *Versions) Execute() error {
+	source, err := c.TemplateSource()
+	if err != nil {
+		return err
+	}
+	tags, err := source.GetAllTags()
+	if err != nil {
+		return err
+	}
+
+	names, err := c.selectVersions(tags)
+	if err != nil {
+		return err
+	}
+
+	if c.Format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(names)
+	}
+
+	if len(names) == 0 {
+		fmt.Println("No version found")
+		return nil
+	}
+	for _, name := range names {
+		fmt.Println(name)
+	}
+	return nil
+}
+
+// selectVersions returns the names of the versions of the given tags matching
+// the constraint and flags of the command, from the highest to the lowest.
+func (c *Versions) selectVersions(tags []git.Tag) ([]string, error) {
+	versions, err := git.MatchVersions(c.Constraint, git.FilterVersions(tags, c.IncludePrerelease))
+	if err != nil {
+		return nil, err
+	}
+	if c.Constraint == git.LatestVersion && len(versions) > 1 {
+		versions = versions[:1]
+	}
+	if c.Top {
+		versions, err = git.GetTopVersions(versions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	names := []string{}
+	for _, version := range versions {
+		names = append(names, version.Original())
+	}
+	return names, nil
+}
+
+// Validate checks if required flags are definded
+func (c *Versions) Validate() error {
+	for _, format := range formats {
+		if c.Format == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid format %q, must be one of: %s", c.Format, formats)
+}
+
+func (c *Versions) setFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(
+		&c.IncludePrerelease,
+		"include-prerelease",
+		false,
+		"Include the prereleases",
+	)
+	flags.BoolVar(
+		&c.Top,
+		"top",
+		false,
+		"Only list the highest patch of each minor version",
+	)
+	flags.StringVarP(
+		&c.Format,
+		"format",
+		"f",
+		"text",
+		"The output format (text or json)",
+	)
+	c.SetTemplateSourceFlags(flags)
+}
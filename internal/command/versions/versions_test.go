@@ -0,0 +1,54 @@
+package versions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/gyv/internal/git"
+)
+
+func testTags() []git.Tag {
+	tags := []git.Tag{}
+	for _, name := range []string{"v5.0.0-rc.1", "v4.4.1", "v4.4.0", "v4.3.5", "v4.3.0", "nightly", "v4.0.0", "v3.9.1", "v3.9.0"} {
+		tags = append(tags, git.Tag{Name: name})
+	}
+	return tags
+}
+
+func TestSelectVersions(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		desc     string
+		command  *Versions
+		expected []string
+	}{
+		{desc: "all", command: &Versions{}, expected: []string{"v4.4.1", "v4.4.0", "v4.3.5", "v4.3.0", "v4.0.0", "v3.9.1", "v3.9.0"}},
+		{desc: "prerelease", command: &Versions{IncludePrerelease: true}, expected: []string{"v5.0.0-rc.1", "v4.4.1", "v4.4.0", "v4.3.5", "v4.3.0", "v4.0.0", "v3.9.1", "v3.9.0"}},
+		{desc: "constraint", command: &Versions{Constraint: "~4.3"}, expected: []string{"v4.3.5", "v4.3.0"}},
+		{desc: "latest", command: &Versions{Constraint: "latest"}, expected: []string{"v4.4.1"}},
+		{desc: "latest prerelease", command: &Versions{Constraint: "latest", IncludePrerelease: true}, expected: []string{"v5.0.0-rc.1"}},
+		{desc: "top", command: &Versions{Top: true}, expected: []string{"v4.4.1", "v4.3.5", "v4.0.0", "v3.9.1"}},
+		{desc: "top constraint", command: &Versions{Constraint: "^4", Top: true}, expected: []string{"v4.4.1", "v4.3.5", "v4.0.0"}},
+		{desc: "top latest", command: &Versions{Constraint: "latest", Top: true}, expected: []string{"v4.4.1"}},
+		{desc: "no match", command: &Versions{Constraint: "^6"}, expected: []string{}},
+		{desc: "no match top", command: &Versions{Constraint: "^6", Top: true}, expected: []string{}},
+	}
+	for _, c := range cases {
+		names, err := c.command.selectVersions(testTags())
+		if assert.Nil(err, c.desc) {
+			assert.Equal(c.expected, names, c.desc)
+		}
+	}
+
+	_, err := (&Versions{Constraint: "not a version"}).selectVersions(testTags())
+	assert.NotNil(err)
+}
+
+func TestVersionsValidate(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil((&Versions{Format: "text"}).Validate())
+	assert.Nil((&Versions{Format: "json"}).Validate())
+	assert.NotNil((&Versions{Format: ""}).Validate())
+	assert.NotNil((&Versions{Format: "csv"}).Validate())
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// EnvVariable the name of the environment variable Goyave reads
+	// to select the configuration file to load.
+	EnvVariable = "GOYAVE_ENV"
+
+	// LocalEnv the default environment, using "config.json".
+	LocalEnv = "local"
+)
+
+// ErrConfigNotFound returned when the configuration file matching
+// the requested environment doesn't exist in the project.
+var ErrConfigNotFound = errors.New("Configuration file not found")
+
+// Config the raw content of a Goyave configuration file.
+type Config map[string]interface{}
+
+// Database the database connection information found in a configuration file.
+type Database struct {
+	Connection string
+	Host       string
+	Port       string
+	Name       string
+}
+
+// String returns a human-readable description of the database connection.
+func (d *Database) String() string {
+	if d.Connection == "" || d.Connection == "none" {
+		return "none"
+	}
+	host := d.Host
+	if d.Port != "" {
+		host += ":" + d.Port
+	}
+	if host == "" {
+		return fmt.Sprintf("%s %q", d.Connection, d.Name)
+	}
+	return fmt.Sprintf("%s %q on %s", d.Connection, d.Name, host)
+}
+
+// FileName returns the name of the configuration file Goyave loads
+// for the given environment.
+func FileName(env string) string {
+	env = strings.ToLower(env)
+	if env == "" || env == LocalEnv || env == "localhost" {
+		return "config.json"
+	}
+	return "config." + env + ".json"
+}
+
+// FindEnvironments returns the environments having a configuration file
+// in the given project directory. The "example" configuration is ignored.
+func FindEnvironments(projectPath string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(projectPath, "config*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	environments := []string{}
+	for _, f := range files {
+		name := filepath.Base(f)
+		if name == "config.json" {
+			environments = append(environments, LocalEnv)
+			continue
+		}
+		env := strings.TrimSuffix(strings.TrimPrefix(name, "config."), ".json")
+		if env == "" || env == name || env == "example" {
+			continue
+		}
+		environments = append(environments, env)
+	}
+
+	sort.SliceStable(environments, func(i, j int) bool {
+		return environments[i] == LocalEnv && environments[j] != LocalEnv
+	})
+	return environments, nil
+}
+
+// Load reads the configuration file matching the given environment in the given project directory.
+func Load(projectPath string, env string) (Config, error) {
+	path := filepath.Join(projectPath, FileName(env))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrConfigNotFound, path)
+		}
+		return nil, err
+	}
+
+	config := Config{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+// Get returns the value identified by the given dot-separated path (e.g.: "database.host"),
+// or nil if it doesn't exist.
+func (c Config) Get(key string) interface{} {
+	var current interface{} = map[string]interface{}(c)
+	for _, part := range strings.Split(key, ".") {
+		category, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = category[part]
+	}
+	return current
+}
+
+// GetString returns the value identified by the given dot-separated path
+// converted to a string, or an empty string if it doesn't exist.
+func (c Config) GetString(key string) string {
+	value := c.Get(key)
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// Database returns the database connection information of this configuration.
+func (c Config) Database() *Database {
+	return &Database{
+		Connection: c.GetString("database.connection"),
+		Host:       c.GetString("database.host"),
+		Port:       c.GetString("database.port"),
+		Name:       c.GetString("database.name"),
+	}
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileName(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("config.json", FileName(""))
+	assert.Equal("config.json", FileName("local"))
+	assert.Equal("config.json", FileName("localhost"))
+	assert.Equal("config.staging.json", FileName("Staging"))
+}
+
+func TestFindEnvironmentsAndLoad(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	for _, name := range []string{"config.test.json", "config.json", "config.example.json", "config.staging.json"} {
+		content := `{"database": {"connection": "mysql", "host": "127.0.0.1", "port": 3306, "name": "shop"}}`
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	environments, err := FindEnvironments(dir)
+	assert.Nil(err)
+	assert.Equal([]string{"local", "staging", "test"}, environments)
+
+	cfg, err := Load(dir, "staging")
+	assert.Nil(err)
+	assert.Equal("mysql", cfg.GetString("database.connection"))
+	assert.Equal("3306", cfg.GetString("database.port"))
+	assert.Nil(cfg.Get("database.name.invalid"))
+	assert.Equal(`mysql "shop" on 127.0.0.1:3306`, cfg.Database().String())
+
+	_, err = Load(dir, "production")
+	assert.True(errors.Is(err, ErrConfigNotFound))
+}
+
+func TestLoadProject(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+
+	project, err := LoadProject(dir)
+	assert.Nil(err)
+	assert.Equal(&Project{}, project)
+
+	content := `{"build": {"tags": ["sqlite"], "goflags": "-trimpath", "toolchain": "go1.21.5", "vendor": true}}`
+	if err := os.WriteFile(filepath.Join(dir, ProjectFileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	project, err = LoadProject(dir)
+	assert.Nil(err)
+	assert.Equal(Build{Tags: []string{"sqlite"}, GoFlags: "-trimpath", Toolchain: "go1.21.5", Vendor: true}, project.Build)
+
+	merged := project.Build.Merge(Build{Tags: []string{"postgres", "json1"}, Go: "/usr/local/bin/go"})
+	assert.Equal(Build{Tags: []string{"postgres", "json1"}, GoFlags: "-trimpath", Go: "/usr/local/bin/go", Toolchain: "go1.21.5", Vendor: true}, merged)
+
+	content = `{"openapi": {"output": "docs/openapi.yaml", "title": "Shop", "servers": [{"url": "https://api.example.org"}], "securitySchemes": {"bearerAuth": {"type": "http", "scheme": "bearer"}}}}`
+	if err := os.WriteFile(filepath.Join(dir, ProjectFileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	project, err = LoadProject(dir)
+	assert.Nil(err)
+	openapi := project.OpenAPI.Merge(OpenAPI{
+		Title:           "Shop API",
+		Version:         "1.2.0",
+		SecuritySchemes: map[string]interface{}{"basicAuth": map[string]interface{}{"type": "http", "scheme": "basic"}},
+	})
+	assert.Equal("docs/openapi.yaml", openapi.Output)
+	assert.Equal("Shop API", openapi.Title)
+	assert.Equal("1.2.0", openapi.Version)
+	assert.Equal([]OpenAPIServer{{URL: "https://api.example.org"}}, openapi.Servers)
+	assert.Len(openapi.SecuritySchemes, 2)
+	assert.Len(project.OpenAPI.SecuritySchemes, 1)
+	assert.Equal(DefaultMigrationRoot, project.Database.MigrationDirectory())
+
+	content = `{"database": {"migrationRoot": "db/migrations/"}}`
+	if err := os.WriteFile(filepath.Join(dir, ProjectFileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	project, err = LoadProject(dir)
+	assert.Nil(err)
+	assert.Equal("db/migrations", project.Database.MigrationDirectory())
+
+	if err := os.WriteFile(filepath.Join(dir, ProjectFileName), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadProject(dir)
+	assert.NotNil(err)
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// ProjectFileName the name of the file containing the gyv configuration
+	// of a project, at the root of the project.
+	ProjectFileName = "gyv.json"
+
+	// DefaultMigrationRoot the directory containing the versioned
+	// migrations of the Goyave project template.
+	DefaultMigrationRoot = "database/migration"
+)
+
+// Project the gyv configuration of a project.
+type Project struct {
+	Build    Build          `json:"build"`
+	OpenAPI  OpenAPI        `json:"openapi"`
+	Database DatabaseLayout `json:"database"`
+}
+
+// DatabaseLayout the layout of the database code of a project.
+type DatabaseLayout struct {
+	// MigrationRoot the slash-separated path to the directory containing the versioned
+	// migrations, relative to the project root. Defaults to "DefaultMigrationRoot".
+	MigrationRoot string `json:"migrationRoot"`
+}
+
+// MigrationDirectory returns the migration root, or "DefaultMigrationRoot" if not set.
+func (d DatabaseLayout) MigrationDirectory() string {
+	if d.MigrationRoot == "" {
+		return DefaultMigrationRoot
+	}
+	return strings.Trim(d.MigrationRoot, "/")
+}
+
+// Build the options of the builds of the code injected into a project.
+type Build struct {
+	// Tags the build tags (e.g.: "sqlite").
+	Tags []string `json:"tags"`
+
+	// GoFlags the flags added to the "GOFLAGS" environment variable.
+	GoFlags string `json:"goflags"`
+
+	// Go the path to the go binary. Defaults to "go".
+	Go string `json:"go"`
+
+	// Toolchain the value of the "GOTOOLCHAIN" environment variable (e.g.: "go1.21.5").
+	Toolchain string `json:"toolchain"`
+
+	// Vendor builds using the project's "vendor" directory.
+	Vendor bool `json:"vendor"`
+}
+
+// Merge returns a copy of these build options overridden
+// by the non-empty fields of the given options.
+func (b Build) Merge(override Build) Build {
+	if len(override.Tags) > 0 {
+		b.Tags = override.Tags
+	}
+	if override.GoFlags != "" {
+		b.GoFlags = override.GoFlags
+	}
+	if override.Go != "" {
+		b.Go = override.Go
+	}
+	if override.Toolchain != "" {
+		b.Toolchain = override.Toolchain
+	}
+	b.Vendor = b.Vendor || override.Vendor
+	return b
+}
+
+// OpenAPI the options of the OpenAPI specification generation.
+type OpenAPI struct {
+	// Output the path to the generated specification, relative to the project
+	// root if not absolute. "-" writes it to the standard output.
+	Output string `json:"output"`
+
+	// Format the format of the generated specification ("json" or "yaml").
+	// Inferred from the output's extension if empty.
+	Format string `json:"format"`
+
+	// Title the title of the API ("info.title").
+	Title string `json:"title"`
+
+	// Version the version of the API ("info.version").
+	Version string `json:"version"`
+
+	// Description the description of the API ("info.description").
+	Description string `json:"description"`
+
+	// Servers the servers of the API, replacing the generated ones.
+	Servers []OpenAPIServer `json:"servers"`
+
+	// SecuritySchemes the security schemes added to the components of the
+	// specification, identified by their name (e.g.: {"bearerAuth": {"type": "http", "scheme": "bearer"}}).
+	SecuritySchemes map[string]interface{} `json:"securitySchemes"`
+
+	// Security the security requirements applied to all operations
+	// (e.g.: [{"bearerAuth": []}]).
+	Security []map[string][]string `json:"security"`
+}
+
+// OpenAPIServer a server of the API.
+type OpenAPIServer struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// Merge returns a copy of these OpenAPI options overridden by the
+// non-empty fields of the given options. Security schemes are merged.
+func (o OpenAPI) Merge(override OpenAPI) OpenAPI {
+	if override.Output != "" {
+		o.Output = override.Output
+	}
+	if override.Format != "" {
+		o.Format = override.Format
+	}
+	if override.Title != "" {
+		o.Title = override.Title
+	}
+	if override.Version != "" {
+		o.Version = override.Version
+	}
+	if override.Description != "" {
+		o.Description = override.Description
+	}
+	if len(override.Servers) > 0 {
+		o.Servers = override.Servers
+	}
+	if len(override.SecuritySchemes) > 0 {
+		schemes := make(map[string]interface{}, len(o.SecuritySchemes)+len(override.SecuritySchemes))
+		for name, scheme := range o.SecuritySchemes {
+			schemes[name] = scheme
+		}
+		for name, scheme := range override.SecuritySchemes {
+			schemes[name] = scheme
+		}
+		o.SecuritySchemes = schemes
+	}
+	if len(override.Security) > 0 {
+		o.Security = override.Security
+	}
+	return o
+}
+
+// LoadProject reads the gyv configuration file of the project in the given directory.
+// If the project doesn't have a configuration file, an empty configuration is returned.
+func LoadProject(projectPath string) (*Project, error) {
+	project := &Project{}
+	path := filepath.Join(projectPath, ProjectFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return project, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, project); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return project, nil
+}
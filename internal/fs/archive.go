@@ -0,0 +1,527 @@
+package fs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ArchiveFormat the format of an archive supported by ExtractArchive.
+type ArchiveFormat string
+
+const (
+	// FormatZip zip archives.
+	FormatZip ArchiveFormat = "zip"
+
+	// FormatTarGz gzipped tarballs.
+	FormatTarGz ArchiveFormat = "tar.gz"
+)
+
+// ErrArchiveLimit returned when an archive exceeds the limits of the extraction.
+var ErrArchiveLimit = errors.New("archive too large")
+
+// ArchiveLimits limits protecting the extraction against decompression bombs.
+// A zero value disables the limit.
+type ArchiveLimits struct {
+	// MaxEntries the maximum number of entries (files, directories, links).
+	MaxEntries int
+	// MaxFileSize the maximum uncompressed size of a single file, in bytes.
+	MaxFileSize int64
+	// MaxTotalSize the maximum uncompressed size of all the files, in bytes.
+	MaxTotalSize int64
+}
+
+// ExtractOptions options of ExtractArchive.
+type ExtractOptions struct {
+	// StripRoot removes the root directory from the extracted paths
+	// if all the entries of the archive are inside the same directory
+	// (e.g.: "template-v4.0.0/" in the archives of a repository).
+	StripRoot bool
+
+	Limits ArchiveLimits
+}
+
+// DefaultExtractOptions the options used by ExtractZip and ExtractTarGz. The limits are far above
+// the size of a project template, but low enough to stop a decompression bomb.
+var DefaultExtractOptions = ExtractOptions{
+	StripRoot: true,
+	Limits: ArchiveLimits{
+		MaxEntries:   10000,
+		MaxFileSize:  100 << 20,
+		MaxTotalSize: 500 << 20,
+	},
+}
+
+// archiveEntry an entry of an archive, independent of the archive's format.
+type archiveEntry struct {
+	name     string
+	mode     os.FileMode
+	size     int64
+	linkname string
+	open     func() (io.ReadCloser, error)
+}
+
+// ExtractArchive extracts the given zip or gzipped tar archive (detected from its content)
+// to the destination directory and returns the paths of the extracted files, directories
+// and symbolic links. The entries escaping the destination directory, either with their path
+// or with the target of a symbolic link, are rejected. The permissions of the entries are
+// preserved, except the special bits (setuid, setgid, sticky). Other types of entries, such as
+// hard links or devices, are ignored.
+func ExtractArchive(filename, destination string, options ExtractOptions) ([]string, error) {
+	format, err := DetectArchiveFormat(filename)
+	if err != nil {
+		return nil, err
+	}
+	return extractArchive(filename, destination, format, options)
+}
+
+// DetectArchiveFormat returns the format of the given archive, detected from its first bytes.
+func DetectArchiveFormat(filename string) (ArchiveFormat, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	magic := make([]byte, 4)
+	n, err := io.ReadFull(file, magic)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	magic = magic[:n]
+	switch {
+	case bytes.HasPrefix(magic, []byte("PK\x03\x04")), bytes.HasPrefix(magic, []byte("PK\x05\x06")):
+		return FormatZip, nil
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		return FormatTarGz, nil
+	}
+	return "", fmt.Errorf("%s: unsupported archive format, expected zip or tar.gz", filename)
+}
+
+func extractArchive(filename, destination string, format ArchiveFormat, options ExtractOptions) ([]string, error) {
+	walk := walkZip
+	if format == FormatTarGz {
+		walk = walkTarGz
+	}
+
+	// First pass: check the limits and find the root directory
+	// without writing anything.
+	root := ""
+	entries := 0
+	var totalSize int64
+	err := walk(filename, func(entry *archiveEntry) error {
+		if entry.name == ".." || strings.HasPrefix(entry.name, "../") || strings.HasPrefix(entry.name, "/") {
+			return fmt.Errorf("%s: illegal file path", entry.name)
+		}
+		entries++
+		totalSize += entry.size
+		if err := options.Limits.check(entries, entry.size, totalSize); err != nil {
+			return fmt.Errorf("%s: %w", entry.name, err)
+		}
+		if options.StripRoot {
+			root = commonRoot(root, entries == 1, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	extractor := &extractor{destination: filepath.Clean(destination), limits: options.Limits}
+	err = walk(filename, func(entry *archiveEntry) error {
+		name := entry.name
+		if root != "" {
+			name = strings.TrimPrefix(strings.TrimPrefix(name, root), "/")
+			if name == "" {
+				return nil // The root directory itself
+			}
+		}
+		return extractor.extract(name, entry)
+	})
+	if err != nil {
+		return extractor.filenames, err
+	}
+	return extractor.filenames, extractor.finish()
+}
+
+// commonRoot returns the root directory shared by the given entry and the previous
+// entries, or an empty string if they don't share one.
+func commonRoot(root string, first bool, entry *archiveEntry) string {
+	parts := strings.SplitN(entry.name, "/", 2)
+	if len(parts) < 2 && !entry.mode.IsDir() {
+		return "" // File at the root of the archive
+	}
+	if first {
+		return parts[0]
+	}
+	if parts[0] != root {
+		return ""
+	}
+	return root
+}
+
+func (l ArchiveLimits) check(entries int, size, totalSize int64) error {
+	switch {
+	case l.MaxEntries > 0 && entries > l.MaxEntries:
+		return fmt.Errorf("%w: more than %d entries", ErrArchiveLimit, l.MaxEntries)
+	case l.MaxFileSize > 0 && size > l.MaxFileSize:
+		return fmt.Errorf("%w: file larger than %d bytes", ErrArchiveLimit, l.MaxFileSize)
+	case l.MaxTotalSize > 0 && totalSize > l.MaxTotalSize:
+		return fmt.Errorf("%w: more than %d bytes uncompressed", ErrArchiveLimit, l.MaxTotalSize)
+	}
+	return nil
+}
+
+type symlink struct {
+	path   string
+	target string
+}
+
+type directory struct {
+	path string
+	mode os.FileMode
+}
+
+// extractor writes the entries of an archive to the destination directory.
+// The symbolic links and the permissions of the directories are applied once all the
+// files are written, so no file can be written through a link or denied by a read-only directory.
+type extractor struct {
+	destination string
+	limits      ArchiveLimits
+	written     int64
+	filenames   []string
+	symlinks    []symlink
+	directories []directory
+}
+
+func (e *extractor) extract(name string, entry *archiveEntry) error {
+	fpath, err := e.path(name)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case entry.mode.IsDir():
+		if err := os.MkdirAll(fpath, 0755); err != nil {
+			return err
+		}
+		mode := entry.mode.Perm()
+		if mode == 0 {
+			mode = 0755
+		}
+		e.directories = append(e.directories, directory{path: fpath, mode: mode})
+	case entry.mode&os.ModeSymlink != 0:
+		target, err := e.linkTarget(fpath, entry)
+		if err != nil {
+			return err
+		}
+		e.symlinks = append(e.symlinks, symlink{path: fpath, target: target})
+	case entry.mode.IsRegular():
+		if err := e.writeFile(fpath, entry); err != nil {
+			return err
+		}
+	default:
+		return nil // Devices, pipes, hard links...
+	}
+	e.filenames = append(e.filenames, fpath)
+	return nil
+}
+
+// path returns the path of the given entry in the destination directory, rejecting the
+// absolute paths and the paths escaping the destination (e.g.: "../../etc/passwd").
+func (e *extractor) path(name string) (string, error) {
+	if strings.HasPrefix(name, "/") || strings.Contains(name, "\\") || filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return "", fmt.Errorf("%s: illegal file path", name)
+	}
+	fpath := filepath.Join(e.destination, filepath.FromSlash(name))
+	if !e.contains(fpath) || fpath == e.destination {
+		return "", fmt.Errorf("%s: illegal file path", name)
+	}
+	return fpath, nil
+}
+
+func (e *extractor) contains(path string) bool {
+	return strings.HasPrefix(path, e.destination+string(os.PathSeparator))
+}
+
+// linkTarget returns the target of the given symbolic link, ensuring it is
+// relative and doesn't point outside of the destination directory.
+func (e *extractor) linkTarget(fpath string, entry *archiveEntry) (string, error) {
+	target := entry.linkname
+	if entry.open != nil { // The target of a symbolic link is the content of a zip entry
+		r, err := entry.open()
+		if err != nil {
+			return "", err
+		}
+		data, err := io.ReadAll(io.LimitReader(r, 4096))
+		_ = r.Close()
+		if err != nil {
+			return "", err
+		}
+		target = string(data)
+	}
+
+	if target == "" || path.IsAbs(target) || filepath.IsAbs(target) || strings.Contains(target, "\\") {
+		return "", fmt.Errorf("%s: illegal symbolic link target %q", entry.name, target)
+	}
+	resolved := filepath.Join(filepath.Dir(fpath), filepath.FromSlash(target))
+	if resolved != e.destination && !e.contains(resolved) {
+		return "", fmt.Errorf("%s: illegal symbolic link target %q", entry.name, target)
+	}
+	return filepath.FromSlash(target), nil
+}
+
+func (e *extractor) writeFile(fpath string, entry *archiveEntry) error {
+	if err := os.MkdirAll(filepath.Dir(fpath), 0755); err != nil {
+		return err
+	}
+	mode := entry.mode.Perm()
+	if mode == 0 {
+		mode = 0644
+	}
+
+	r, err := entry.open()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := r.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	// The sizes declared in the headers are checked during the first pass,
+	// but the actual content may be larger.
+	var limit int64 = -1
+	if e.limits.MaxFileSize > 0 {
+		limit = e.limits.MaxFileSize
+	}
+	if e.limits.MaxTotalSize > 0 && (limit < 0 || e.limits.MaxTotalSize-e.written < limit) {
+		limit = e.limits.MaxTotalSize - e.written
+	}
+	var reader io.Reader = r
+	if limit >= 0 {
+		reader = io.LimitReader(r, limit+1)
+	}
+	n, err := io.Copy(outFile, reader)
+	e.written += n
+	if err == nil && limit >= 0 && n > limit {
+		err = fmt.Errorf("%s: %w", entry.name, ErrArchiveLimit)
+	}
+	if err != nil {
+		_ = outFile.Close()
+		return err
+	}
+	if err := outFile.Close(); err != nil {
+		return err
+	}
+	return os.Chmod(fpath, mode) // Not affected by the umask
+}
+
+// finish creates the symbolic links, ensuring they don't resolve outside of the
+// destination directory, and applies the permissions of the directories.
+func (e *extractor) finish() error {
+	for _, link := range e.symlinks {
+		if err := os.MkdirAll(filepath.Dir(link.path), 0755); err != nil {
+			return err
+		}
+		if err := os.Symlink(link.target, link.path); err != nil {
+			return err
+		}
+	}
+
+	if len(e.symlinks) > 0 {
+		// The targets are checked lexically before being created, but a link
+		// can also go through other links (e.g.: "a -> ." and "b -> a/..").
+		destination, err := filepath.EvalSymlinks(e.destination)
+		if err != nil {
+			return err
+		}
+		for _, link := range e.symlinks {
+			resolved, err := resolveLink(link.path)
+			if err == nil && resolved != destination && !strings.HasPrefix(resolved, destination+string(os.PathSeparator)) {
+				err = fmt.Errorf("%s: symbolic link resolves outside of %s", link.path, e.destination)
+			}
+			if err != nil {
+				e.removeSymlinks()
+				return err
+			}
+		}
+	}
+
+	for i := len(e.directories) - 1; i >= 0; i-- {
+		dir := e.directories[i]
+		if err := os.Chmod(dir.path, dir.mode|0700); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveLink returns the path the given symbolic link resolves to. Unlike "filepath.EvalSymlinks",
+// dangling links don't fail: the components following a missing one are resolved lexically, as if
+// they were directories, so a link that could escape once the missing files are created is detected.
+func resolveLink(link string) (string, error) {
+	link, err := filepath.Abs(link)
+	if err != nil {
+		return "", err
+	}
+	return resolvePath(link, 0)
+}
+
+func resolvePath(p string, depth int) (string, error) {
+	if depth > 255 {
+		return "", fmt.Errorf("%s: too many levels of symbolic links", p)
+	}
+	volume := filepath.VolumeName(p)
+	current := volume + string(os.PathSeparator)
+	parts := strings.Split(p[len(volume):], string(os.PathSeparator))
+	for i, part := range parts {
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			current = filepath.Dir(current)
+			continue
+		}
+		next := filepath.Join(current, part)
+		info, err := os.Lstat(next)
+		if os.IsNotExist(err) {
+			return filepath.Join(append([]string{next}, parts[i+1:]...)...), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(next)
+			if err != nil {
+				return "", err
+			}
+			if !filepath.IsAbs(target) {
+				// Not joined with "filepath.Join()", which would clean
+				// the ".." components of the target lexically.
+				target = strings.TrimSuffix(current, string(os.PathSeparator)) + string(os.PathSeparator) + target
+			}
+			next, err = resolvePath(target, depth+1)
+			if err != nil {
+				return "", err
+			}
+		}
+		current = next
+	}
+	return current, nil
+}
+
+func (e *extractor) removeSymlinks() {
+	for _, link := range e.symlinks {
+		if err := os.Remove(link.path); err != nil && !os.IsNotExist(err) {
+			log.Println(err)
+		}
+	}
+}
+
+func walkZip(filename string, fn func(*archiveEntry) error) error {
+	r, err := zip.OpenReader(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := r.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	for _, f := range r.File {
+		file := f
+		entry := &archiveEntry{
+			name: strings.TrimPrefix(path.Clean(file.Name), "./"),
+			mode: file.Mode(),
+			size: int64(file.UncompressedSize64),
+			open: func() (io.ReadCloser, error) { return file.Open() },
+		}
+		if strings.HasSuffix(file.Name, "/") {
+			entry.mode |= os.ModeDir
+		}
+		if file.Name == "" || entry.name == "." {
+			continue
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walkTarGz(filename string, fn func(*archiveEntry) error) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	reader := tar.NewReader(gzipReader)
+
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		entry := &archiveEntry{
+			name: strings.TrimPrefix(path.Clean(header.Name), "./"),
+			mode: os.FileMode(header.Mode).Perm(),
+			size: header.Size,
+		}
+		switch header.Typeflag {
+		case tar.TypeXGlobalHeader, tar.TypeXHeader:
+			continue // Metadata (e.g.: "pax_global_header" of git archives)
+		case tar.TypeDir:
+			entry.mode |= os.ModeDir
+			entry.size = 0
+		case tar.TypeSymlink:
+			entry.mode |= os.ModeSymlink
+			entry.linkname = header.Linkname
+			entry.size = 0
+		case tar.TypeReg, tar.TypeRegA:
+			entry.open = func() (io.ReadCloser, error) { return io.NopCloser(reader), nil }
+		default:
+			entry.mode |= os.ModeIrregular
+			entry.size = 0
+		}
+		if entry.name == "." {
+			continue
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package fs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testArchiveEntry struct {
+	name    string
+	content string
+	mode    os.FileMode
+}
+
+var testArchiveEntries = []testArchiveEntry{
+	{name: "template-v4.0.0/", mode: os.ModeDir | 0755},
+	{name: "template-v4.0.0/go.mod", content: "module goyave_template\n", mode: 0644},
+	{name: "template-v4.0.0/http/route/route.go", content: "package route\n", mode: 0644},
+	{name: "template-v4.0.0/run.sh", content: "#!/bin/sh\n", mode: 0755},
+}
+
+func writeTestZip(t *testing.T, path string, entries []testArchiveEntry) {
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer := zip.NewWriter(file)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+		header.SetMode(e.mode)
+		w, err := writer.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(e.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeTestTarGz(t *testing.T, path string, entries []testArchiveEntry) {
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gzipWriter := gzip.NewWriter(file)
+	writer := tar.NewWriter(gzipWriter)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Mode: int64(e.mode.Perm()), Size: int64(len(e.content)), Typeflag: tar.TypeReg}
+		if e.mode.IsDir() {
+			header.Typeflag = tar.TypeDir
+			header.Size = 0
+		} else if e.mode&os.ModeSymlink != 0 {
+			header.Typeflag = tar.TypeSymlink
+			header.Linkname = e.content
+			header.Size = 0
+		}
+		if err := writer.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			if _, err := writer.Write([]byte(e.content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	for _, c := range []interface{ Close() error }{writer, gzipWriter, file} {
+		if err := c.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func assertExtractedTemplate(t *testing.T, dir string) {
+	assert := assert.New(t)
+	content, err := os.ReadFile(filepath.Join(dir, "http", "route", "route.go"))
+	assert.Nil(err)
+	assert.Equal("package route\n", string(content))
+	info, err := os.Stat(filepath.Join(dir, "run.sh"))
+	if assert.Nil(err) {
+		assert.Equal(os.FileMode(0755), info.Mode().Perm())
+	}
+}
+
+func TestExtractTarGz(t *testing.T) {
+	assert := assert.New(t)
+	archive := filepath.Join(t.TempDir(), "template.tar.gz")
+	writeTestTarGz(t, archive, testArchiveEntries)
+
+	dir := filepath.Join(t.TempDir(), "project")
+	files, err := ExtractTarGz(archive, dir)
+	assert.Nil(err)
+	assert.Len(files, 3)
+	assertExtractedTemplate(t, dir)
+
+	writeTestTarGz(t, archive, []testArchiveEntry{{name: "template/../../evil.go", content: "package evil\n", mode: 0644}})
+	_, err = ExtractTarGz(archive, filepath.Join(t.TempDir(), "project"))
+	assert.NotNil(err)
+}
+
+func TestExtractArchive(t *testing.T) {
+	for _, format := range []ArchiveFormat{FormatZip, FormatTarGz} {
+		format := format
+		t.Run(string(format), func(t *testing.T) {
+			assert := assert.New(t)
+			write := writeTestZip
+			if format == FormatTarGz {
+				write = writeTestTarGz
+			}
+			archive := filepath.Join(t.TempDir(), "template")
+			extract := func(entries []testArchiveEntry, options ExtractOptions) (string, []string, error) {
+				write(t, archive, entries)
+				dir := filepath.Join(t.TempDir(), "project")
+				files, err := ExtractArchive(archive, dir, options)
+				return dir, files, err
+			}
+
+			detected, err := func() (ArchiveFormat, error) {
+				write(t, archive, testArchiveEntries)
+				return DetectArchiveFormat(archive)
+			}()
+			assert.Nil(err)
+			assert.Equal(format, detected)
+
+			// Without root directory entry, the common root is still removed
+			dir, files, err := extract(append(testArchiveEntries[1:], testArchiveEntry{name: "template-v4.0.0/link", content: "http/route", mode: os.ModeSymlink | 0777}), DefaultExtractOptions)
+			assert.Nil(err)
+			assert.Len(files, 4)
+			assertExtractedTemplate(t, dir)
+			target, err := os.Readlink(filepath.Join(dir, "link"))
+			assert.Nil(err)
+			assert.Equal(filepath.FromSlash("http/route"), target)
+
+			// Dangling links inside the destination
+			dir, _, err = extract(append(testArchiveEntries,
+				testArchiveEntry{name: "template-v4.0.0/config.json", content: "config.example.json", mode: os.ModeSymlink | 0777},
+				testArchiveEntry{name: "template-v4.0.0/http/storage", content: "../storage/public", mode: os.ModeSymlink | 0777},
+			), DefaultExtractOptions)
+			assert.Nil(err)
+			assertExtractedTemplate(t, dir)
+			target, err = os.Readlink(filepath.Join(dir, "http", "storage"))
+			assert.Nil(err)
+			assert.Equal(filepath.FromSlash("../storage/public"), target)
+
+			// Files at the root of the archive
+			dir, _, err = extract([]testArchiveEntry{
+				{name: "go.mod", content: "module goyave_template\n", mode: 0644},
+				{name: "http/route/route.go", content: "package route\n", mode: 0644},
+				{name: "run.sh", content: "#!/bin/sh\n", mode: 0755},
+			}, DefaultExtractOptions)
+			assert.Nil(err)
+			assertExtractedTemplate(t, dir)
+
+			// Path traversal and symlink escapes
+			illegal := [][]testArchiveEntry{
+				{{name: "template/../../evil.go", content: "package evil\n", mode: 0644}},
+				{{name: "/etc/evil.go", content: "package evil\n", mode: 0644}},
+				{{name: "template/link", content: "/etc", mode: os.ModeSymlink | 0777}},
+				{{name: "template/link", content: "../..", mode: os.ModeSymlink | 0777}},
+				{
+					{name: "template/a", content: ".", mode: os.ModeSymlink | 0777},
+					{name: "template/b", content: "a/..", mode: os.ModeSymlink | 0777},
+				},
+				{
+					{name: "template/a", content: ".", mode: os.ModeSymlink | 0777},
+					{name: "template/b", content: "a/../missing", mode: os.ModeSymlink | 0777},
+				},
+			}
+			for _, entries := range illegal {
+				dir, _, err := extract(entries, DefaultExtractOptions)
+				assert.NotNil(err, entries[len(entries)-1].name)
+				_, err = os.Lstat(filepath.Join(dir, "b"))
+				assert.True(os.IsNotExist(err))
+			}
+
+			// Decompression bombs
+			limits := []ArchiveLimits{{MaxEntries: 2}, {MaxFileSize: 12}, {MaxTotalSize: 30}}
+			for _, l := range limits {
+				_, _, err := extract(testArchiveEntries, ExtractOptions{StripRoot: true, Limits: l})
+				assert.True(errors.Is(err, ErrArchiveLimit), l)
+			}
+		})
+	}
+}
+
+func TestCopyDirectory(t *testing.T) {
+	assert := assert.New(t)
+	archive := filepath.Join(t.TempDir(), "template.zip")
+	writeTestZip(t, archive, testArchiveEntries)
+	source := filepath.Join(t.TempDir(), "template")
+	_, err := ExtractZip(archive, source)
+	assert.Nil(err)
+	if err := os.MkdirAll(filepath.Join(source, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(source, ".git", "HEAD"), []byte("ref"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("http", "route"), filepath.Join(source, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("config.example.json", filepath.Join(source, "config.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	destination := filepath.Join(t.TempDir(), "project")
+	files, err := CopyDirectory(source, destination)
+	assert.Nil(err)
+	sort.Strings(files)
+	assert.Equal([]string{
+		filepath.Join(destination, "config.json"),
+		filepath.Join(destination, "go.mod"),
+		filepath.Join(destination, "http", "route", "route.go"),
+		filepath.Join(destination, "link"),
+		filepath.Join(destination, "run.sh"),
+	}, files)
+	assertExtractedTemplate(t, destination)
+	_, err = os.Stat(filepath.Join(destination, ".git"))
+	assert.True(os.IsNotExist(err))
+	target, err := os.Readlink(filepath.Join(destination, "link"))
+	assert.Nil(err)
+	assert.Equal(filepath.Join("http", "route"), target)
+	assert.Nil(ReplaceAll(destination, "example/project"))
+
+	// Symbolic links resolving outside of the template
+	illegal := []string{filepath.Join("..", "outside"), filepath.Join(t.TempDir(), "absolute")}
+	for _, linkTarget := range illegal {
+		if err := os.Remove(filepath.Join(source, "link")); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(linkTarget, filepath.Join(source, "link")); err != nil {
+			t.Fatal(err)
+		}
+		_, err = CopyDirectory(source, filepath.Join(t.TempDir(), "project"))
+		assert.NotNil(err, linkTarget)
+	}
+}
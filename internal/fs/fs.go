@@ -19,8 +19,8 @@ func ReplaceAll(projectName string, moduleName string) error {
 		if err != nil {
 			return err
 		}
-		if info.IsDir() {
-			return nil
+		if !info.Type().IsRegular() {
+			return nil // Directories and symbolic links
 		}
 
 		ext := filepath.Ext(path)
@@ -81,6 +81,77 @@ func CopyFile(source, destination string) error {
 	return out.Close()
 }
 
+// CopyDirectory copy the content of the given source directory to the given
+// destination directory, which is created if needed. ".git" directories are ignored.
+// Symbolic links are copied as is if they don't resolve outside of the source directory.
+// Other non-regular files (e.g.: devices, sockets) are not supported.
+// Returns the paths of the copied files.
+func CopyDirectory(source, destination string) ([]string, error) {
+	var filenames []string
+	resolvedSource, err := filepath.EvalSymlinks(source)
+	if err != nil {
+		return nil, err
+	}
+	err = filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(destination, relativePath)
+
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, os.ModePerm)
+		}
+		if d.Type()&os.ModeSymlink != 0 {
+			if err := copySymlink(resolvedSource, path, target); err != nil {
+				return err
+			}
+			filenames = append(filenames, target)
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return fmt.Errorf("%s: unsupported file type %s", path, d.Type())
+		}
+
+		if err := CopyFile(path, target); err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		filenames = append(filenames, target)
+		return os.Chmod(target, info.Mode().Perm())
+	})
+	return filenames, err
+}
+
+// copySymlink creates a symbolic link at the given target with the same target as the
+// given link, ensuring it is relative and doesn't resolve outside of the source directory.
+func copySymlink(source, link, target string) error {
+	linkTarget, err := os.Readlink(link)
+	if err != nil {
+		return err
+	}
+	if filepath.IsAbs(linkTarget) {
+		return fmt.Errorf("%s: illegal symbolic link target %q", link, linkTarget)
+	}
+	resolved, err := resolveLink(link)
+	if err != nil {
+		return err
+	}
+	if resolved != source && !strings.HasPrefix(resolved, source+string(os.PathSeparator)) {
+		return fmt.Errorf("%s: symbolic link resolves outside of the template", link)
+	}
+	return os.Symlink(linkTarget, target)
+}
+
 // CreateResourceFile create a resource file from a stub
 func CreateResourceFile(path string, name string, data []byte) error {
 	var filePath string
@@ -3,6 +3,7 @@ package fs
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/Masterminds/semver"
 )
@@ -61,3 +62,13 @@ func CreateModelPath(modelName string, projectPath string, goyaveVersion *semver
 
 	return path, nil
 }
+
+// CreateMigrationPath generate the path to the versioned migrations of a Goyave project,
+// located in the given slash-separated migration root
+func CreateMigrationPath(projectPath string, migrationRoot string) string {
+	if projectPath == "" {
+		return filepath.FromSlash(migrationRoot)
+	}
+
+	return fmt.Sprintf("%s%c%s", projectPath, os.PathSeparator, filepath.FromSlash(migrationRoot))
+}
@@ -0,0 +1,7 @@
+package fs
+
+// ExtractTarGz extracts a gzipped tarball to the given directory, removing the root
+// directory of the archive if all the entries are inside it. See ExtractArchive.
+func ExtractTarGz(filename string, projectName string) ([]string, error) {
+	return extractArchive(filename, projectName, FormatTarGz, DefaultExtractOptions)
+}
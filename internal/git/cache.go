@@ -0,0 +1,60 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultTemplateRepository the repository of the official Goyave project template.
+const DefaultTemplateRepository = "go-goyave/template"
+
+// TemplateCache a directory storing the downloaded project templates, so projects
+// can be created offline with a version that was already downloaded once.
+type TemplateCache struct {
+	Directory string
+}
+
+// NewTemplateCache returns the template cache located in the user cache directory
+// (e.g.: "~/.cache/gyv/templates" on Linux).
+func NewTemplateCache() (*TemplateCache, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateCache{Directory: filepath.Join(dir, "gyv", "templates")}, nil
+}
+
+// Path returns the path to the cached archive of the given version of the template
+// repository, identified by its cache key (e.g.: "github.com/go-goyave/template",
+// see Source.CacheKey()). The file may not exist.
+func (c *TemplateCache) Path(repository, version string) string {
+	return filepath.Join(c.Directory, filepath.FromSlash(repository), strings.TrimPrefix(version, "v")+".zip")
+}
+
+// Lookup returns the path to the cached archive of the given version
+// of the template repository, and true if it exists.
+func (c *TemplateCache) Lookup(repository, version string) (string, bool) {
+	path := c.Path(repository, version)
+	info, err := os.Stat(path)
+	return path, err == nil && info.Mode().IsRegular()
+}
+
+// Download downloads the archive of the given tag from the given source to the cache and
+// returns its path. The archive is only added to the cache once it is fully downloaded.
+func (c *TemplateCache) Download(source Source, tag *Tag, version string) (string, error) {
+	path := c.Path(source.CacheKey(), version)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	tmp := path + ".download"
+	if err := source.Download(tag, tmp); err != nil {
+		_ = os.Remove(tmp)
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return "", err
+	}
+	return path, nil
+}
@@ -4,11 +4,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/Masterminds/semver"
 )
 
+// LatestVersion the version query selecting the highest available version.
+const LatestVersion = "latest"
+
+var exactVersionRegex = regexp.MustCompile(`^v?[0-9]+\.[0-9]+\.[0-9]+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+
 // Tag represent github api response
 type Tag struct {
 	Name       string `json:"name"`
@@ -53,7 +60,10 @@ func (tag *Tag) getVersion() (*semver.Version, error) {
 // GetTopVersions return all major and minor versions
 // Only the highest patch version is taken
 func GetTopVersions(versions []*semver.Version) ([]*semver.Version, error) {
-	filteredVersions := versions[:1]
+	if len(versions) == 0 {
+		return versions, nil
+	}
+	filteredVersions := []*semver.Version{versions[0]}
 	for _, version := range versions {
 		exist, err := majorMinorExist(version, filteredVersions)
 		if err != nil {
@@ -118,60 +128,139 @@ func VersionsToStrings(versions []*semver.Version) []string {
 
 // GetAllTags return all Goyave tags registered inside Github API
 func GetAllTags() ([]Tag, error) {
-	goyaveTagsURL := "https://api.github.com/repos/go-goyave/template/tags"
-	bodyContent, link, err := getHTTPData(goyaveTagsURL)
-	if err != nil {
-		return nil, err
-	}
-
-	var responsesBytes [][]byte
+	return DefaultRepository().GetAllTags()
+}
 
-	responsesBytes = append(responsesBytes, bodyContent)
-	linkData, err := getLinksData(responsesBytes, link)
+// GetTagByName search a tag from a string version and a list of tags
+func GetTagByName(name string, tags []Tag) (*Tag, error) {
+	toCheck, err := semver.NewVersion(name)
 	if err != nil {
 		return nil, err
 	}
 
-	responsesBytes = append(responsesBytes, linkData...)
+	for _, tag := range tags {
+		version, err := semver.NewVersion(tag.Name)
+		if err != nil {
+			continue // Not a version (e.g.: "nightly")
+		}
 
-	var tags []Tag
-	for _, responseBytes := range responsesBytes {
-		var tagsResponse []Tag
-		if err := json.Unmarshal(responseBytes, &tagsResponse); err != nil {
+		constraint, err := semver.NewConstraint(version.String())
+		if err != nil {
 			return nil, err
 		}
 
-		tags = append(tags, tagsResponse...)
-
+		if constraint.Check(toCheck) {
+			return &tag, nil
+		}
 	}
 
-	return tags, nil
+	return nil, fmt.Errorf("No tag found for: %s", name)
 }
 
-// GetTagByName search a tag from a string version and a list of tags
-func GetTagByName(name string, tags []Tag) (*Tag, error) {
-	toCheck, err := semver.NewVersion(name)
+// FilterVersions returns the versions of the given tags, sorted from the highest to the lowest.
+// Tags that are not semantic versions are ignored, so are the prereleases unless includePrerelease is true.
+func FilterVersions(tags []Tag, includePrerelease bool) []*semver.Version {
+	versions := []*semver.Version{}
+	for _, tag := range tags {
+		version, err := tag.getVersion()
+		if err != nil || (version.Prerelease() != "" && !includePrerelease) {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	sort.Sort(sort.Reverse(semver.Collection(versions)))
+	return versions
+}
+
+// ResolveVersion returns the tag with the highest version matching the given query: an exact
+// version (e.g.: "v4.0.0"), a constraint (e.g.: "^4", "~4.3", ">= 4.2, < 4.5") or "latest".
+// Prereleases are only selected by exact versions or if includePrerelease is true.
+func ResolveVersion(query string, tags []Tag, includePrerelease bool) (*Tag, error) {
+	query = strings.TrimSpace(query)
+	if IsExactVersion(query) {
+		return GetTagByName(query, tags)
+	}
+
+	versions, err := MatchVersions(query, FilterVersions(tags, includePrerelease))
 	if err != nil {
 		return nil, err
 	}
+	if len(versions) == 0 {
+		if query == "" {
+			query = LatestVersion
+		}
+		return nil, fmt.Errorf("No version found for: %s", query)
+	}
+	return findTag(versions[0].Original(), tags), nil
+}
 
-	for _, tag := range tags {
-		version, err := semver.NewVersion(tag.Name)
+// MatchVersions returns the given versions matching the query: an exact version, a constraint or
+// "latest" (or an empty query), which matches all versions. A prerelease matches a constraint if
+// its release version does (e.g.: "v5.0.0-rc.1" matches "^5").
+func MatchVersions(query string, versions []*semver.Version) ([]*semver.Version, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || query == LatestVersion {
+		return versions, nil
+	}
+
+	if IsExactVersion(query) {
+		version, err := semver.NewVersion(query)
 		if err != nil {
 			return nil, err
 		}
+		matching := []*semver.Version{}
+		for _, v := range versions {
+			if v.Equal(version) {
+				matching = append(matching, v)
+			}
+		}
+		return matching, nil
+	}
 
-		constraint, err := semver.NewConstraint(version.String())
+	constraint, err := semver.NewConstraint(query)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid version %q: expected a version, a constraint (e.g.: \"^4\", \"~4.3\") or %q", query, LatestVersion)
+	}
+	matching := []*semver.Version{}
+	for _, version := range versions {
+		release, err := version.SetPrerelease("")
 		if err != nil {
 			return nil, err
 		}
+		if constraint.Check(&release) {
+			matching = append(matching, version)
+		}
+	}
+	return matching, nil
+}
 
-		if constraint.Check(toCheck) {
-			return &tag, nil
+// IsExactVersion returns true if the given version query designates
+// a single version (e.g.: "v4.0.0") rather than a constraint.
+func IsExactVersion(query string) bool {
+	return exactVersionRegex.MatchString(query)
+}
+
+func findTag(name string, tags []Tag) *Tag {
+	for i := range tags {
+		if tags[i].Name == name {
+			return &tags[i]
 		}
 	}
+	return nil
+}
 
-	return nil, fmt.Errorf("No tag found for: %s", name)
+// FindTagByCommit returns the tag pointing to the commit identified by the given
+// SHA (or SHA prefix of at least 7 characters), or nil if there is none.
+func FindTagByCommit(sha string, tags []Tag) *Tag {
+	if len(sha) < 7 {
+		return nil
+	}
+	for i := range tags {
+		if strings.HasPrefix(tags[i].Commit.SHA, strings.ToLower(sha)) {
+			return &tags[i]
+		}
+	}
+	return nil
 }
 
 // ProjectGitInit initialize a git project
@@ -0,0 +1,89 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/stretchr/testify/assert"
+)
+
+func testTags() []Tag {
+	tags := []Tag{}
+	for _, name := range []string{"v5.0.0-rc.1", "v4.4.0", "v4.3.5", "v4.3.0", "nightly", "v4.0.0", "v3.9.1"} {
+		tag := Tag{Name: name}
+		tag.Commit.SHA = "0123456789abcdef" + name
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func TestResolveVersion(t *testing.T) {
+	assert := assert.New(t)
+	tags := testTags()
+
+	cases := []struct {
+		query             string
+		includePrerelease bool
+		expected          string
+	}{
+		{query: "", expected: "v4.4.0"},
+		{query: "latest", expected: "v4.4.0"},
+		{query: "latest", includePrerelease: true, expected: "v5.0.0-rc.1"},
+		{query: "^4", expected: "v4.4.0"},
+		{query: "~4.3", expected: "v4.3.5"},
+		{query: "< 4.0.0", expected: "v3.9.1"},
+		{query: "4.3.0", expected: "v4.3.0"},
+		{query: "v4.0.0", expected: "v4.0.0"},
+		{query: "v5.0.0-rc.1", expected: "v5.0.0-rc.1"},
+		{query: "^5", includePrerelease: true, expected: "v5.0.0-rc.1"},
+	}
+	for _, c := range cases {
+		tag, err := ResolveVersion(c.query, tags, c.includePrerelease)
+		if assert.Nil(err, c.query) {
+			assert.Equal(c.expected, tag.Name, c.query)
+		}
+	}
+
+	_, err := ResolveVersion("^5", tags, false)
+	assert.NotNil(err)
+	_, err = ResolveVersion("v4.1.0", tags, false)
+	assert.NotNil(err)
+	_, err = ResolveVersion("not a version", tags, false)
+	assert.NotNil(err)
+}
+
+func TestFilterVersions(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal([]string{"4.4.0", "4.3.5", "4.3.0", "4.0.0", "3.9.1"}, VersionsToStrings(FilterVersions(testTags(), false)))
+	assert.Equal("5.0.0-rc.1", FilterVersions(testTags(), true)[0].String())
+
+	versions, err := MatchVersions("~4.3", FilterVersions(testTags(), false))
+	assert.Nil(err)
+	assert.Equal([]string{"4.3.5", "4.3.0"}, VersionsToStrings(versions))
+}
+
+func TestGetTopVersions(t *testing.T) {
+	assert := assert.New(t)
+	versions := FilterVersions(testTags(), false)
+	top, err := GetTopVersions(versions)
+	assert.Nil(err)
+	assert.Equal([]string{"4.4.0", "4.3.5", "4.0.0", "3.9.1"}, VersionsToStrings(top))
+
+	// The given versions are not modified
+	assert.Equal([]string{"4.4.0", "4.3.5", "4.3.0", "4.0.0", "3.9.1"}, VersionsToStrings(versions))
+
+	top, err = GetTopVersions([]*semver.Version{})
+	assert.Nil(err)
+	assert.Empty(top)
+}
+
+func TestFindTagByCommit(t *testing.T) {
+	assert := assert.New(t)
+	tags := testTags()
+	assert.Nil(FindTagByCommit("0123", tags))
+	assert.Nil(FindTagByCommit("fedcba9876", tags))
+	tag := FindTagByCommit("0123456789abcdefv4.0.0", tags)
+	if assert.NotNil(tag) {
+		assert.Equal("v4.0.0", tag.Name)
+	}
+}
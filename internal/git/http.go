@@ -24,21 +24,36 @@ var (
 	GitClient HTTPClient = &http.Client{Timeout: 30 * time.Second}
 )
 
-func getLinksData(bodyList [][]byte, link string) ([][]byte, error) {
+// getLinksData follows the pagination links. The token sent to each
+// page is given by the tokenFor function, depending on its URL.
+func getLinksData(bodyList [][]byte, link string, tokenFor func(url string) string) ([][]byte, error) {
 	if link == "" {
 		return bodyList, nil
 	}
 
-	bytes, link, err := getHTTPData(link)
+	bytes, link, err := getHTTPData(link, tokenFor(link))
 	if err != nil {
 		return nil, err
 	}
 
-	return getLinksData(append(bodyList, bytes), link)
+	return getLinksData(append(bodyList, bytes), link, tokenFor)
 }
 
-func getHTTPData(url string) ([]byte, string, error) {
+// newRequest creates a GET request, authenticated with
+// the given token if not empty.
+func newRequest(url string, token string) (*http.Request, error) {
 	request, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		request.Header.Set("Authorization", "token "+token)
+	}
+	return request, nil
+}
+
+func getHTTPData(url string, token string) ([]byte, string, error) {
+	request, err := newRequest(url, token)
 	if err != nil {
 		return nil, "", err
 	}
@@ -85,7 +100,11 @@ func getNextPageURL(rawLinks string) string {
 
 // DownloadFile download a file from given URL and writes it to the given filename
 func DownloadFile(url string, filename string) error {
-	request, err := http.NewRequest("GET", url, nil)
+	return downloadFile(url, filename, "")
+}
+
+func downloadFile(url string, filename string, token string) error {
+	request, err := newRequest(url, token)
 	if err != nil {
 		return err
 	}
@@ -109,7 +128,7 @@ func DownloadFile(url string, filename string) error {
 		return fmt.Errorf(string(data))
 	}
 
-	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY, 0644)
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,349 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"golang.org/x/mod/sumdb"
+	"golang.org/x/mod/sumdb/dirhash"
+	modzip "golang.org/x/mod/zip"
+)
+
+const (
+	// DefaultTemplateModule the module path of the official Goyave project template.
+	DefaultTemplateModule = "goyave.dev/template"
+
+	defaultGoProxy = "https://proxy.golang.org,direct"
+	defaultSumDB   = "sum.golang.org"
+)
+
+// errNotFound returned by the proxies when a module or version doesn't exist,
+// allowing to fall back to the next proxy of a comma-separated GOPROXY list.
+var errNotFound = errors.New("not found")
+
+// ModuleProxy a template source using the Go module proxy protocol ("@v/list"
+// to list the versions, "@v/<version>.zip" to download them), so the template
+// can be retrieved through the same proxies as the project's dependencies.
+type ModuleProxy struct {
+	// Module the module path of the template (e.g.: "goyave.dev/template").
+	Module string
+
+	// GoProxy the list of proxies to use, with the syntax of the GOPROXY environment variable.
+	// The "direct" entries are ignored: this source doesn't support version control systems.
+	GoProxy string
+
+	// GoNoProxy the patterns of the modules that must not be downloaded
+	// through a proxy (GONOPROXY, defaults to GOPRIVATE).
+	GoNoProxy string
+
+	// GoSumDB the checksum database verifying the downloaded archives (GOSUMDB).
+	// "off" disables the verification.
+	GoSumDB string
+
+	// GoNoSumDB the patterns of the modules that are not verified using
+	// the checksum database (GONOSUMDB, defaults to GOPRIVATE).
+	GoNoSumDB string
+
+	// SumDBDirectory the directory storing the latest signed tree of the checksum
+	// databases and the cached tiles (e.g.: "~/.cache/gyv/sumdb" on Linux).
+	SumDBDirectory string
+}
+
+// NewModuleProxy returns a module proxy source for the given template module, configured
+// from the GOPROXY, GONOPROXY, GOSUMDB, GONOSUMDB and GOPRIVATE settings of the go command.
+func NewModuleProxy(modulePath string) (*ModuleProxy, error) {
+	if modulePath == "" {
+		modulePath = DefaultTemplateModule
+	}
+	if err := module.CheckPath(modulePath); err != nil {
+		return nil, fmt.Errorf("Invalid template module: %w", err)
+	}
+
+	env := goEnv("GOPROXY", "GONOPROXY", "GOPRIVATE", "GOSUMDB", "GONOSUMDB")
+	proxy := &ModuleProxy{
+		Module:    modulePath,
+		GoProxy:   env["GOPROXY"],
+		GoNoProxy: env["GONOPROXY"],
+		GoSumDB:   env["GOSUMDB"],
+		GoNoSumDB: env["GONOSUMDB"],
+	}
+	if proxy.GoProxy == "" {
+		proxy.GoProxy = defaultGoProxy
+	}
+	if proxy.GoNoProxy == "" {
+		proxy.GoNoProxy = env["GOPRIVATE"]
+	}
+	if proxy.GoSumDB == "" {
+		proxy.GoSumDB = defaultSumDB
+	}
+	if proxy.GoNoSumDB == "" {
+		proxy.GoNoSumDB = env["GOPRIVATE"]
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	proxy.SumDBDirectory = filepath.Join(cacheDir, "gyv", "sumdb")
+	return proxy, nil
+}
+
+// goEnv returns the values of the given variables in the configuration of the go
+// command (including the values set with "go env -w"). Falls back to the
+// environment variables if the go command is not available.
+func goEnv(names ...string) map[string]string {
+	env := map[string]string{}
+	output, err := exec.Command("go", append([]string{"env", "-json"}, names...)...).Output()
+	if err == nil && json.Unmarshal(output, &env) == nil {
+		return env
+	}
+	for _, name := range names {
+		env[name] = os.Getenv(name)
+	}
+	return env
+}
+
+// GetAllTags lists the versions of the template module available in the proxies.
+// The tags only contain the version name.
+func (p *ModuleProxy) GetAllTags() ([]Tag, error) {
+	var tags []Tag
+	err := p.fetch("@v/list", func(response *http.Response) error {
+		tags = []Tag{}
+		scanner := bufio.NewScanner(response.Body)
+		for scanner.Scan() {
+			version := strings.Fields(scanner.Text())
+			if len(version) == 0 || !semver.IsValid(version[0]) {
+				continue
+			}
+			tags = append(tags, Tag{Name: version[0]})
+		}
+		return scanner.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(versionNames(tags))
+	return tags, nil
+}
+
+// ResolveRef asks the proxies for the version of the template module at the
+// given branch or commit. Untagged commits are identified by a pseudo-version
+// (e.g.: "v0.0.0-20210101120000-0123456789ab").
+func (p *ModuleProxy) ResolveRef(ref string) (*Tag, error) {
+	escapedRef, err := module.EscapeVersion(ref)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid branch or commit %q: %w", ref, err)
+	}
+	info := struct {
+		Version string
+	}{}
+	err = p.fetch("@v/"+escapedRef+".info", func(response *http.Response) error {
+		return json.NewDecoder(response.Body).Decode(&info)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !semver.IsValid(info.Version) {
+		return nil, fmt.Errorf("%s: invalid version %q returned for %s", p.Module, info.Version, ref)
+	}
+	return &Tag{Name: info.Version}, nil
+}
+
+// Download downloads the module zip of the given version and verifies
+// it using the checksum database.
+func (p *ModuleProxy) Download(tag *Tag, filename string) error {
+	escapedVersion, err := module.EscapeVersion(tag.Name)
+	if err != nil {
+		return err
+	}
+	err = p.fetch("@v/"+escapedVersion+".zip", func(response *http.Response) error {
+		file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(file, response.Body); err != nil {
+			_ = file.Close()
+			return err
+		}
+		return file.Close()
+	})
+	if err != nil {
+		return err
+	}
+	return p.verify(tag.Name, filename)
+}
+
+// Extract extracts a module zip, validating its content.
+func (p *ModuleProxy) Extract(archive, directory string) ([]string, error) {
+	if err := modzip.Unzip(directory, module.Version{Path: p.Module, Version: versionFromArchive(archive)}, archive); err != nil {
+		return nil, err
+	}
+	var filenames []string
+	err := filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			filenames = append(filenames, path)
+		}
+		return err
+	})
+	return filenames, err
+}
+
+// CacheKey returns the slash-separated path identifying the template
+// module in the template cache (e.g.: "proxy/goyave.dev/template").
+func (p *ModuleProxy) CacheKey() string {
+	return "proxy/" + p.Module
+}
+
+// fetch requests the given file of the template module (e.g.: "@v/list") from the proxies.
+func (p *ModuleProxy) fetch(file string, handle func(*http.Response) error) error {
+	if module.MatchPrefixPatterns(p.GoNoProxy, p.Module) {
+		return fmt.Errorf("%s matches GONOPROXY or GOPRIVATE and cannot be downloaded through a module proxy: use the %q source", p.Module, SourceGitHub)
+	}
+	escapedPath, err := module.EscapePath(p.Module)
+	if err != nil {
+		return err
+	}
+
+	err = p.eachProxy(func(proxy string) error {
+		return p.fetchFromProxy(proxy+"/"+escapedPath+"/"+file, handle)
+	})
+	if errors.Is(err, errNoProxy) {
+		err = fmt.Errorf("no module proxy available in GOPROXY=%q", p.GoProxy)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", p.Module, err)
+	}
+	return nil
+}
+
+// errNoProxy returned by eachProxy if GOPROXY doesn't contain any proxy URL.
+var errNoProxy = errors.New("no module proxy")
+
+// eachProxy calls try with the URL of each proxy of GOPROXY until it succeeds, following
+// the fallback rules of GOPROXY: after an entry followed by a comma, the next proxy is only
+// tried if the module or version is not found. After a pipe, the next proxy is tried on any error.
+func (p *ModuleProxy) eachProxy(try func(proxy string) error) error {
+	var lastErr error
+	proxies := p.GoProxy
+	for proxies != "" {
+		var proxy string
+		fallbackOnError := false
+		if i := strings.IndexAny(proxies, ",|"); i >= 0 {
+			proxy = proxies[:i]
+			fallbackOnError = proxies[i] == '|'
+			proxies = proxies[i+1:]
+		} else {
+			proxy, proxies = proxies, ""
+		}
+		proxy = strings.TrimSpace(proxy)
+
+		switch proxy {
+		case "":
+			continue
+		case "off":
+			return fmt.Errorf("%s cannot be downloaded: module downloads are disabled by GOPROXY=off", p.Module)
+		case "direct", "noproxy":
+			continue
+		}
+
+		err := try(strings.TrimSuffix(proxy, "/"))
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !fallbackOnError && !errors.Is(err, errNotFound) {
+			return err
+		}
+	}
+
+	if lastErr == nil {
+		return errNoProxy
+	}
+	return lastErr
+}
+
+func (p *ModuleProxy) fetchFromProxy(url string, handle func(*http.Response) error) error {
+	request, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	response, err := GitClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := response.Body.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	switch {
+	case response.StatusCode == http.StatusNotFound || response.StatusCode == http.StatusGone:
+		data, _ := io.ReadAll(response.Body)
+		return fmt.Errorf("%s: %w: %s", url, errNotFound, bytes.TrimSpace(data))
+	case response.StatusCode > 299:
+		return fmt.Errorf("%s: %s", url, response.Status)
+	}
+	return handle(response)
+}
+
+// verify checks the hash of the given module zip against the checksum database, unless
+// disabled by GOSUMDB=off or GONOSUMDB/GOPRIVATE. Like the go command, the records are
+// authenticated with the GOSUMDB key and their inclusion in the database's signed tree is
+// verified. The database is accessed through the proxies if they support it.
+func (p *ModuleProxy) verify(version, filename string) error {
+	if p.GoSumDB == "off" || module.MatchPrefixPatterns(p.GoNoSumDB, p.Module) {
+		return nil
+	}
+
+	hash, err := dirhash.HashZip(filename, dirhash.Hash1)
+	if err != nil {
+		return err
+	}
+
+	ops, err := newSumDBOps(p)
+	if err != nil {
+		return err
+	}
+	client := sumdb.NewClient(ops)
+	lines, err := client.Lookup(p.Module, version)
+	if err != nil {
+		return fmt.Errorf("Cannot verify %s@%s using the checksum database (set GONOSUMDB to skip the verification): %w", p.Module, version, err)
+	}
+
+	expected := ""
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[0] == p.Module && fields[1] == version {
+			expected = fields[2]
+			break
+		}
+	}
+	if expected != hash {
+		return fmt.Errorf("Checksum mismatch for %s@%s: downloaded %s, checksum database %s", p.Module, version, hash, expected)
+	}
+	return nil
+}
+
+// versionFromArchive returns the version of a cached module zip from its file name.
+func versionFromArchive(archive string) string {
+	return "v" + strings.TrimSuffix(filepath.Base(archive), ".zip")
+}
+
+type versionNames []Tag
+
+func (v versionNames) Len() int           { return len(v) }
+func (v versionNames) Less(i, j int) bool { return semver.Compare(v[i].Name, v[j].Name) > 0 }
+func (v versionNames) Swap(i, j int)      { v[i], v[j] = v[j], v[i] }
@@ -0,0 +1,203 @@
+package git
+
+import (
+	"archive/zip"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mod/sumdb"
+	"golang.org/x/mod/sumdb/dirhash"
+	"golang.org/x/mod/sumdb/note"
+)
+
+func writeModuleZip(t *testing.T, filename, prefix string) {
+	file, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(file)
+	for name, content := range map[string]string{
+		"go.mod":              "module example.org/starter\n",
+		"main.go":             "package main\n",
+		"config.example.json": "{}",
+	} {
+		f, err := w.Create(prefix + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newTestSumDB returns a stand-in checksum database named "sum.example.org"
+// containing "example.org/starter" v1.1.0 (with the hash of the given archive)
+// and v1.0.0 (with a wrong hash), and its verifier key.
+func newTestSumDB(t *testing.T, archive string) (http.Handler, string) {
+	hash, err := dirhash.HashZip(archive, dirhash.Hash1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, verifier, err := note.GenerateKey(rand.Reader, "sum.example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := sumdb.NewTestServer(signer, func(path, version string) ([]byte, error) {
+		switch version {
+		case "v1.1.0":
+			return []byte(fmt.Sprintf("%s %s %s\n%s %s/go.mod h1:xRKHG7Yk7rKUaqqETpEbL5UjGS6xcmFbOi6NfnWTmrQ=\n", path, version, hash, path, version)), nil
+		case "v1.0.0":
+			return []byte(fmt.Sprintf("%s %s h1:tampered=\n", path, version)), nil
+		}
+		return nil, fmt.Errorf("%s@%s not found", path, version)
+	})
+	return sumdb.NewServer(server), verifier
+}
+
+// newTestProxy starts a stand-in module proxy serving "example.org/starter" v1.0.0
+// and v1.1.0, also serving the checksum database "sum.example.org".
+// Returns the proxy, the archive of v1.1.0 and the verifier key of the checksum database.
+func newTestProxy(t *testing.T) (*httptest.Server, string, string) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "v1.1.0.zip")
+	writeModuleZip(t, archive, "example.org/starter@v1.1.0/")
+	sumDB, key := newTestSumDB(t, archive)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/example.org/starter/@v/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "v1.0.0\nv1.1.0\nnot-a-version\n")
+	})
+	mux.HandleFunc("/example.org/starter/@v/main.info", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"Version": "v1.1.1-0.20210901120000-0123456789ab", "Time": "2021-09-01T12:00:00Z"}`)
+	})
+	mux.HandleFunc("/example.org/starter/@v/v1.1.0.zip", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, archive)
+	})
+	mux.Handle("/sumdb/sum.example.org/", http.StripPrefix("/sumdb/sum.example.org", sumDB))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, archive, key
+}
+
+func TestModuleProxyGetAllTags(t *testing.T) {
+	assert := assert.New(t)
+	server, _, _ := newTestProxy(t)
+
+	proxy := &ModuleProxy{Module: "example.org/starter", GoProxy: server.URL, GoSumDB: "off"}
+	tags, err := proxy.GetAllTags()
+	assert.Nil(err)
+	if assert.Len(tags, 2) {
+		assert.Equal("v1.1.0", tags[0].Name)
+		assert.Equal("v1.0.0", tags[1].Name)
+	}
+	assert.Equal("proxy/example.org/starter", proxy.CacheKey())
+
+	// Fall back to the next proxy on "not found" (comma) or any error (pipe)
+	tag, err := proxy.ResolveRef("main")
+	if assert.Nil(err) {
+		assert.Equal("v1.1.1-0.20210901120000-0123456789ab", tag.Name)
+	}
+	_, err = proxy.ResolveRef("unknown")
+	assert.NotNil(err)
+
+	proxy.GoProxy = server.URL + "/missing," + server.URL
+	tags, err = proxy.GetAllTags()
+	assert.Nil(err)
+	assert.Len(tags, 2)
+
+	proxy.GoProxy = "http://127.0.0.1:0|" + server.URL
+	_, err = proxy.GetAllTags()
+	assert.Nil(err)
+
+	proxy.GoProxy = "http://127.0.0.1:0," + server.URL
+	_, err = proxy.GetAllTags()
+	assert.NotNil(err)
+
+	proxy.GoProxy = "direct"
+	_, err = proxy.GetAllTags()
+	assert.NotNil(err)
+
+	proxy.GoProxy = "off"
+	_, err = proxy.GetAllTags()
+	assert.NotNil(err)
+
+	proxy.GoProxy = server.URL
+	proxy.GoNoProxy = "example.org/*"
+	_, err = proxy.GetAllTags()
+	assert.NotNil(err)
+}
+
+func TestModuleProxyDownload(t *testing.T) {
+	assert := assert.New(t)
+	server, source, key := newTestProxy(t)
+	dir := t.TempDir()
+
+	// The checksum database is accessed through the proxy: the direct URL is unreachable
+	proxy := &ModuleProxy{
+		Module:         "example.org/starter",
+		GoProxy:        server.URL,
+		GoSumDB:        key + " http://127.0.0.1:0",
+		SumDBDirectory: filepath.Join(dir, "sumdb"),
+	}
+	archive := filepath.Join(dir, "1.1.0.zip")
+	assert.Nil(proxy.Download(&Tag{Name: "v1.1.0"}, archive))
+	_, err := os.Stat(filepath.Join(dir, "sumdb", "config", "sum.example.org", "latest"))
+	assert.Nil(err)
+
+	project := filepath.Join(dir, "project")
+	files, err := proxy.Extract(archive, project)
+	assert.Nil(err)
+	assert.Len(files, 3)
+	content, err := os.ReadFile(filepath.Join(project, "go.mod"))
+	assert.Nil(err)
+	assert.Equal("module example.org/starter\n", string(content))
+
+	// Checksum mismatch, the checksum database is accessed directly
+	// because the proxy doesn't support it
+	sumDB, _ := newTestSumDB(t, source)
+	sumDBServer := httptest.NewServer(sumDB)
+	defer sumDBServer.Close()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/example.org/starter/@v/v1.0.0.zip", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, archive)
+	})
+	other := httptest.NewServer(mux)
+	defer other.Close()
+	proxy.GoProxy = other.URL
+	proxy.SumDBDirectory = filepath.Join(t.TempDir(), "sumdb")
+	proxy.GoSumDB = key + " " + sumDBServer.URL
+	err = proxy.Download(&Tag{Name: "v1.0.0"}, filepath.Join(dir, "1.0.0.zip"))
+	assert.NotNil(err)
+
+	// The signature of the checksum database doesn't match the key
+	_, otherKey, err := note.GenerateKey(rand.Reader, "sum.example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy.GoProxy = server.URL
+	proxy.GoSumDB = otherKey
+	proxy.SumDBDirectory = filepath.Join(t.TempDir(), "sumdb")
+	err = proxy.Download(&Tag{Name: "v1.1.0"}, filepath.Join(dir, "1.1.0.zip"))
+	assert.NotNil(err)
+
+	_, err = newSumDBOps(&ModuleProxy{GoSumDB: "unknown.example.org"})
+	assert.NotNil(err)
+
+	// Verification disabled
+	proxy.GoProxy = other.URL
+	proxy.GoNoSumDB = "example.org"
+	assert.Nil(proxy.Download(&Tag{Name: "v1.0.0"}, filepath.Join(dir, "1.0.0.zip")))
+}
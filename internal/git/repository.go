@@ -0,0 +1,168 @@
+package git
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// DefaultAPIURL the base URL of the GitHub REST API.
+	DefaultAPIURL = "https://api.github.com"
+
+	// TokenEnvVariable the environment variable containing the token used
+	// to authenticate to the GitHub API. It is not sent to other servers.
+	TokenEnvVariable = "GITHUB_TOKEN"
+
+	githubAPIHost = "api.github.com"
+
+	// CredentialsFileName the name of the file, in the gyv user configuration
+	// directory, containing the tokens used to authenticate to the repositories' APIs.
+	CredentialsFileName = "credentials.json"
+)
+
+// Repository a template repository hosted on GitHub, GitHub Enterprise
+// or a server with a compatible API (e.g.: Gitea).
+type Repository struct {
+	// Name the full name of the repository (e.g.: "go-goyave/template").
+	Name string
+
+	// APIURL the base URL of the REST API (e.g.: "https://github.example.org/api/v3"
+	// for GitHub Enterprise, "https://gitea.example.org/api/v1" for Gitea).
+	APIURL string
+
+	// Token the token sent in the "Authorization" header of the requests to the API
+	// host. Leave empty for public repositories.
+	Token string
+}
+
+// DefaultRepository returns the repository of the official Goyave project template.
+func DefaultRepository() *Repository {
+	return &Repository{Name: DefaultTemplateRepository, APIURL: DefaultAPIURL}
+}
+
+// NewRepository returns the repository with the given name ("owner/name") on the server
+// with the given API base URL (defaults to GitHub). For the GitHub API, the token is read from the
+// "GITHUB_TOKEN" environment variable. Otherwise, or if the variable is not set, the token associated
+// with the API host in the credentials file is used.
+func NewRepository(name, apiURL string) (*Repository, error) {
+	if name == "" {
+		name = DefaultTemplateRepository
+	}
+	parts := strings.Split(name, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("Invalid template repository %q: expected \"owner/name\"", name)
+	}
+
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	u, err := url.Parse(apiURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("Invalid API URL %q", apiURL)
+	}
+
+	repository := &Repository{Name: name, APIURL: strings.TrimSuffix(apiURL, "/")}
+	if u.Host == githubAPIHost {
+		repository.Token = os.Getenv(TokenEnvVariable)
+	}
+	if repository.Token == "" {
+		path, err := credentialsPath()
+		if err != nil {
+			return nil, err
+		}
+		repository.Token, err = LoadToken(path, u.Host)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return repository, nil
+}
+
+// GetAllTags returns all the tags of the repository.
+func (r *Repository) GetAllTags() ([]Tag, error) {
+	bodyContent, link, err := getHTTPData(r.APIURL+"/repos/"+r.Name+"/tags", r.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	responsesBytes, err := getLinksData([][]byte{bodyContent}, link, r.tokenFor)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []Tag
+	for _, responseBytes := range responsesBytes {
+		var tagsResponse []Tag
+		if err := json.Unmarshal(responseBytes, &tagsResponse); err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, tagsResponse...)
+
+	}
+
+	return tags, nil
+}
+
+// DownloadFile downloads a file of the repository (e.g.: a tag's zipball)
+// from the given URL and writes it to the given filename.
+func (r *Repository) DownloadFile(url string, filename string) error {
+	return downloadFile(url, filename, r.tokenFor(url))
+}
+
+// tokenFor returns the token of the repository if the given URL is on the API
+// host, so the token is never sent to another server (e.g.: a download URL
+// returned by the API). Returns an empty string otherwise.
+func (r *Repository) tokenFor(rawURL string) string {
+	api, err := url.Parse(r.APIURL)
+	if err != nil {
+		return ""
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != api.Scheme || !strings.EqualFold(u.Host, api.Host) {
+		return ""
+	}
+	return r.Token
+}
+
+// CacheKey returns the slash-separated path identifying the
+// repository in the template cache (e.g.: "github.com/go-goyave/template").
+func (r *Repository) CacheKey() string {
+	host := r.APIURL
+	if u, err := url.Parse(r.APIURL); err == nil {
+		host = u.Host
+	}
+	host = strings.TrimPrefix(host, "api.")
+	return host + "/" + r.Name
+}
+
+// LoadToken reads the token for the given host (e.g.: "api.github.com") in the given
+// credentials file. The file is a JSON object associating hosts with tokens. Returns
+// an empty string if the file doesn't exist or doesn't contain a token for this host.
+func LoadToken(path, host string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	credentials := map[string]string{}
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	return credentials[host], nil
+}
+
+func credentialsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gyv", CredentialsFileName), nil
+}
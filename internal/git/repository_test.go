@@ -0,0 +1,186 @@
+package git
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockClient struct {
+	requests  []*http.Request
+	responses map[string]*http.Response
+}
+
+func (c *mockClient) Do(request *http.Request) (*http.Response, error) {
+	c.requests = append(c.requests, request)
+	response, ok := c.responses[request.URL.String()]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(bytes.NewBufferString("Not Found"))}, nil
+	}
+	return response, nil
+}
+
+func jsonResponse(body string, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(bytes.NewBufferString(body))}
+}
+
+func TestRepositoryGetAllTags(t *testing.T) {
+	assert := assert.New(t)
+	client := &mockClient{responses: map[string]*http.Response{
+		"https://git.example.org/api/v1/repos/acme/starter/tags": jsonResponse(
+			`[{"name": "v1.1.0", "zipball_url": "https://git.example.org/acme/starter/archive/v1.1.0.zip"}]`,
+			http.Header{"Link": {`<https://git.example.org/api/v1/repos/acme/starter/tags?page=2>; rel="next"`}},
+		),
+		"https://git.example.org/api/v1/repos/acme/starter/tags?page=2": jsonResponse(`[{"name": "v1.0.0"}]`, nil),
+	}}
+	previous := GitClient
+	GitClient = client
+	defer func() { GitClient = previous }()
+
+	repository := &Repository{Name: "acme/starter", APIURL: "https://git.example.org/api/v1", Token: "secret"}
+	tags, err := repository.GetAllTags()
+	assert.Nil(err)
+	if assert.Len(tags, 2) {
+		assert.Equal("v1.1.0", tags[0].Name)
+		assert.Equal("https://git.example.org/acme/starter/archive/v1.1.0.zip", tags[0].ZipballURL)
+		assert.Equal("v1.0.0", tags[1].Name)
+	}
+	for _, r := range client.requests {
+		assert.Equal("token secret", r.Header.Get("Authorization"))
+	}
+	assert.Equal("git.example.org/acme/starter", repository.CacheKey())
+	assert.Equal("github.com/go-goyave/template", DefaultRepository().CacheKey())
+
+	// The token is not sent to other hosts
+	client.requests = nil
+	_ = repository.DownloadFile("https://downloads.example.org/acme/starter/archive/v1.1.0.zip", filepath.Join(t.TempDir(), "archive.zip"))
+	_ = repository.DownloadFile("http://git.example.org/acme/starter/archive/v1.1.0.zip", filepath.Join(t.TempDir(), "archive.zip"))
+	for _, r := range client.requests {
+		assert.Empty(r.Header.Get("Authorization"), r.URL.String())
+	}
+
+	repository.Token = ""
+	client.requests = nil
+	_, _ = repository.GetAllTags()
+	assert.Empty(client.requests[0].Header.Get("Authorization"))
+}
+
+func TestNewRepository(t *testing.T) {
+	assert := assert.New(t)
+	previous, isSet := os.LookupEnv(TokenEnvVariable)
+	if err := os.Setenv(TokenEnvVariable, "env-token"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if isSet {
+			_ = os.Setenv(TokenEnvVariable, previous)
+		} else {
+			_ = os.Unsetenv(TokenEnvVariable)
+		}
+	}()
+
+	repository, err := NewRepository("", "")
+	assert.Nil(err)
+	assert.Equal(DefaultTemplateRepository, repository.Name)
+	assert.Equal(DefaultAPIURL, repository.APIURL)
+	assert.Equal("env-token", repository.Token)
+
+	// GITHUB_TOKEN is only used for GitHub, other hosts use the credentials file
+	config := t.TempDir()
+	previousConfig := os.Getenv("XDG_CONFIG_HOME")
+	if err := os.Setenv("XDG_CONFIG_HOME", config); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Setenv("XDG_CONFIG_HOME", previousConfig) }()
+	repository, err = NewRepository("acme/starter", "https://github.example.org/api/v3/")
+	assert.Nil(err)
+	assert.Equal("https://github.example.org/api/v3", repository.APIURL)
+	assert.Empty(repository.Token)
+
+	if err := os.MkdirAll(filepath.Join(config, "gyv"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(config, "gyv", CredentialsFileName), []byte(`{"github.example.org": "enterprise-token"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	repository, err = NewRepository("acme/starter", "https://github.example.org/api/v3/")
+	assert.Nil(err)
+	assert.Equal("enterprise-token", repository.Token)
+
+	for _, name := range []string{"starter", "acme/", "acme/starter/extra"} {
+		_, err = NewRepository(name, "")
+		assert.NotNil(err, name)
+	}
+	_, err = NewRepository("acme/starter", "github.example.org")
+	assert.NotNil(err)
+}
+
+func TestLoadToken(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), CredentialsFileName)
+
+	token, err := LoadToken(path, "api.github.com")
+	assert.Nil(err)
+	assert.Empty(token)
+
+	if err := os.WriteFile(path, []byte(`{"api.github.com": "github-token", "git.example.org": "gitea-token"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	token, err = LoadToken(path, "git.example.org")
+	assert.Nil(err)
+	assert.Equal("gitea-token", token)
+	token, err = LoadToken(path, "other.example.org")
+	assert.Nil(err)
+	assert.Empty(token)
+
+	if err := os.WriteFile(path, []byte(`{`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadToken(path, "api.github.com")
+	assert.NotNil(err)
+}
+
+func TestRepositoryResolveRef(t *testing.T) {
+	assert := assert.New(t)
+	sha := "8b1e4a0f2d7c9e3b5a6f4d2c1b0a9e8d7c6b5a4f"
+	previous := GitClient
+	defer func() { GitClient = previous }()
+	mock := func() {
+		GitClient = &mockClient{responses: map[string]*http.Response{
+			"https://api.github.com/repos/go-goyave/template/tags": jsonResponse(
+				`[{"name": "v4.0.0", "commit": {"sha": "c0ffee0f2d7c9e3b5a6f4d2c1b0a9e8d7c6b5a4f"}}]`, nil,
+			),
+			"https://api.github.com/repos/go-goyave/template/branches/master": jsonResponse(`{"name": "master", "commit": {"sha": "`+sha+`"}}`, nil),
+			"https://api.github.com/repos/go-goyave/template/commits/8b1e4a0": jsonResponse(`{"sha": "`+sha+`"}`, nil),
+		}}
+	}
+
+	repository := DefaultRepository()
+	for _, ref := range []string{"master", "8b1e4a0"} {
+		mock()
+		tag, err := repository.ResolveRef(ref)
+		if assert.Nil(err, ref) {
+			assert.Equal(sha, tag.Name)
+			assert.Equal(sha, tag.Commit.SHA)
+			assert.Equal("https://api.github.com/repos/go-goyave/template/zipball/"+sha, tag.ZipballURL)
+		}
+	}
+
+	mock()
+	tag, err := repository.ResolveRef("c0ffee0")
+	if assert.Nil(err) {
+		assert.Equal("v4.0.0", tag.Name)
+	}
+
+	mock()
+	_, err = repository.ResolveRef("unknown")
+	assert.NotNil(err)
+}
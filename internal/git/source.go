@@ -0,0 +1,135 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"goyave.dev/gyv/internal/fs"
+)
+
+const (
+	// SourceGitHub lists and downloads the template versions through
+	// the REST API of GitHub (or a compatible server).
+	SourceGitHub = "github"
+
+	// SourceProxy lists and downloads the template versions through
+	// the Go module proxy protocol.
+	SourceProxy = "proxy"
+)
+
+// Source a place where the versions of a project template can be listed and downloaded.
+type Source interface {
+	// GetAllTags returns all the versions of the template.
+	GetAllTags() ([]Tag, error)
+
+	// ResolveRef returns the version of the template at the given branch or commit.
+	// The name of the returned tag identifies this version in the template cache, so
+	// it must not be a moving reference (e.g.: a commit SHA instead of a branch name).
+	ResolveRef(ref string) (*Tag, error)
+
+	// Download downloads the archive of the given version of the template
+	// and writes it to the given filename.
+	Download(tag *Tag, filename string) error
+
+	// Extract extracts an archive downloaded from this source to the given directory
+	// and returns the paths of the extracted files.
+	Extract(archive, directory string) ([]string, error)
+
+	// CacheKey returns the slash-separated path identifying
+	// the template in the template cache.
+	CacheKey() string
+}
+
+// Download downloads the zipball of the given tag, or its tarball if
+// there is no zipball.
+func (r *Repository) Download(tag *Tag, filename string) error {
+	switch {
+	case tag.ZipballURL != "":
+		return r.DownloadFile(tag.ZipballURL, filename)
+	case tag.TarballURL != "":
+		return r.DownloadFile(tag.TarballURL, filename)
+	}
+	return fmt.Errorf("No archive available for: %s", tag.Name)
+}
+
+// Extract extracts a zipball or a tarball of the repository, removing its root directory.
+func (r *Repository) Extract(archive, directory string) ([]string, error) {
+	return fs.ExtractArchive(archive, directory, fs.DefaultExtractOptions)
+}
+
+// ResolveRef returns the version of the repository at the given branch or commit SHA.
+// If the commit is tagged, the tag is returned. Otherwise, the returned tag is named
+// after the full SHA of the commit and points to the archive of this commit.
+func (r *Repository) ResolveRef(ref string) (*Tag, error) {
+	if ref == "" {
+		return nil, fmt.Errorf("Empty branch or commit")
+	}
+	tags, err := r.GetAllTags()
+	if err != nil {
+		return nil, err
+	}
+	if tag := FindTagByCommit(ref, tags); tag != nil {
+		return tag, nil
+	}
+
+	sha, err := r.commitSHA(ref)
+	if err != nil {
+		return nil, fmt.Errorf("No branch or commit found for %s: %w", ref, err)
+	}
+	if tag := FindTagByCommit(sha, tags); tag != nil {
+		return tag, nil
+	}
+
+	tag := &Tag{Name: sha, ZipballURL: r.archiveURL(sha)}
+	tag.Commit.SHA = sha
+	return tag, nil
+}
+
+// commitSHA returns the full SHA of the commit at the head of the given
+// branch, or of the commit identified by the given (abbreviated) SHA.
+func (r *Repository) commitSHA(ref string) (string, error) {
+	escaped := url.PathEscape(ref)
+	body, _, err := getHTTPData(r.APIURL+"/repos/"+r.Name+"/branches/"+escaped, r.Token)
+	if err == nil {
+		branch := struct {
+			Commit struct {
+				SHA string `json:"sha"`
+				ID  string `json:"id"` // Gitea
+			} `json:"commit"`
+		}{}
+		if err := json.Unmarshal(body, &branch); err != nil {
+			return "", err
+		}
+		if branch.Commit.SHA != "" {
+			return branch.Commit.SHA, nil
+		}
+		if branch.Commit.ID != "" {
+			return branch.Commit.ID, nil
+		}
+	}
+
+	body, _, err = getHTTPData(r.APIURL+"/repos/"+r.Name+"/commits/"+escaped, r.Token)
+	if err != nil {
+		return "", err
+	}
+	commit := struct {
+		SHA string `json:"sha"`
+	}{}
+	if err := json.Unmarshal(body, &commit); err != nil {
+		return "", err
+	}
+	if commit.SHA == "" {
+		return "", fmt.Errorf("unexpected response from %s", r.APIURL)
+	}
+	return commit.SHA, nil
+}
+
+// archiveURL returns the URL of the zip archive of the given commit.
+func (r *Repository) archiveURL(sha string) string {
+	if strings.HasSuffix(r.APIURL, "/api/v1") { // Gitea
+		return r.APIURL + "/repos/" + r.Name + "/archive/" + sha + ".zip"
+	}
+	return r.APIURL + "/repos/" + r.Name + "/zipball/" + sha
+}
@@ -0,0 +1,137 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/mod/sumdb"
+)
+
+// knownSumDBs the verifier keys of the checksum databases that can
+// be designated by their name only in GOSUMDB.
+var knownSumDBs = map[string]string{
+	"sum.golang.org":       "sum.golang.org+033de0ae+Ac4zctda0e5eza+HJyk9SxEdh+s3Ue5MaXo2tJu7C4BVK",
+	"sum.golang.google.cn": "sum.golang.org+033de0ae+Ac4zctda0e5eza+HJyk9SxEdh+s3Ue5MaXo2tJu7C4BVK",
+}
+
+// sumDBOps the operations of the checksum database client (see "sumdb.ClientOps").
+// The latest signed tree and the tiles are stored in the directory of the proxy source.
+type sumDBOps struct {
+	proxy *ModuleProxy
+	name  string
+	key   string
+	url   string
+	mu    sync.Mutex
+}
+
+// newSumDBOps parses the GOSUMDB setting of the given proxy source:
+// "<name>", "<key>" or "<name|key> <url>".
+func newSumDBOps(proxy *ModuleProxy) (*sumDBOps, error) {
+	fields := strings.Fields(proxy.GoSumDB)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, fmt.Errorf("Invalid GOSUMDB %q", proxy.GoSumDB)
+	}
+	ops := &sumDBOps{proxy: proxy, key: fields[0]}
+	if !strings.Contains(ops.key, "+") {
+		key, ok := knownSumDBs[ops.key]
+		if !ok {
+			return nil, fmt.Errorf("Invalid GOSUMDB %q: unknown checksum database, the key must be given", proxy.GoSumDB)
+		}
+		ops.url = "https://" + ops.key
+		ops.key = key
+	}
+	ops.name = ops.key[:strings.Index(ops.key, "+")]
+	if ops.url == "" {
+		ops.url = "https://" + ops.name
+	}
+	if len(fields) == 2 {
+		ops.url = strings.TrimSuffix(fields[1], "/")
+	}
+	return ops, nil
+}
+
+// ReadRemote reads the given path of the checksum database through the first proxy
+// supporting it, or directly from the checksum database if no proxy supports it.
+func (o *sumDBOps) ReadRemote(path string) ([]byte, error) {
+	var data []byte
+	read := func(response *http.Response) error {
+		d, err := io.ReadAll(response.Body)
+		data = d
+		return err
+	}
+	err := o.proxy.eachProxy(func(proxy string) error {
+		return o.proxy.fetchFromProxy(proxy+"/sumdb/"+o.name+path, read)
+	})
+	if errors.Is(err, errNotFound) || errors.Is(err, errNoProxy) {
+		err = o.proxy.fetchFromProxy(o.url+path, read)
+	}
+	return data, err
+}
+
+// ReadConfig returns the verifier key or the latest known signed tree.
+func (o *sumDBOps) ReadConfig(file string) ([]byte, error) {
+	if file == "key" {
+		return []byte(o.key), nil
+	}
+	data, err := os.ReadFile(o.path("config", file))
+	if errors.Is(err, os.ErrNotExist) {
+		return []byte{}, nil
+	}
+	return data, err
+}
+
+// WriteConfig replaces the latest known signed tree.
+func (o *sumDBOps) WriteConfig(file string, old, new []byte) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	current, err := o.ReadConfig(file)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(current, old) {
+		return sumdb.ErrWriteConflict
+	}
+	return o.write(o.path("config", file), new)
+}
+
+// ReadCache reads a cached tile or lookup.
+func (o *sumDBOps) ReadCache(file string) ([]byte, error) {
+	return os.ReadFile(o.path("cache", file))
+}
+
+// WriteCache caches a tile or lookup. Errors are ignored: the
+// data is downloaded again if it is not in the cache.
+func (o *sumDBOps) WriteCache(file string, data []byte) {
+	_ = o.write(o.path("cache", file), data)
+}
+
+// Log discards the messages of the client.
+func (o *sumDBOps) Log(msg string) {}
+
+// SecurityError prints the security errors. The client then
+// returns "sumdb.ErrSecurity", which fails the verification.
+func (o *sumDBOps) SecurityError(msg string) {
+	fmt.Fprintln(os.Stderr, msg)
+}
+
+func (o *sumDBOps) path(kind, file string) string {
+	return filepath.Join(o.proxy.SumDBDirectory, kind, filepath.FromSlash(file))
+}
+
+func (o *sumDBOps) write(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
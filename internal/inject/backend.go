@@ -0,0 +1,162 @@
+package inject
+
+import (
+	"errors"
+	"fmt"
+	"plugin"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// Backend the way injected code is built and executed.
+type Backend string
+
+const (
+	// BackendAuto uses the plugin backend, or the exec backend if
+	// Go plugins are not supported or if the plugin cannot be loaded.
+	BackendAuto Backend = "auto"
+
+	// BackendPlugin builds the injected code as a Go plugin and
+	// loads it into gyv's process.
+	BackendPlugin Backend = "plugin"
+
+	// BackendExec builds the injected code as an executable and runs it
+	// in a subprocess, communicating with it over stdin and stdout.
+	BackendExec Backend = "exec"
+)
+
+var (
+	// Backends the list of available backends.
+	Backends = []Backend{BackendAuto, BackendPlugin, BackendExec}
+
+	// DefaultBackend the backend used by new injectors.
+	DefaultBackend = BackendAuto
+
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Validate returns an error if the backend is not one of the available backends.
+func (b Backend) Validate() error {
+	for _, backend := range Backends {
+		if b == backend {
+			return nil
+		}
+	}
+	names := make([]string, 0, len(Backends))
+	for _, backend := range Backends {
+		names = append(names, string(backend))
+	}
+	return fmt.Errorf("invalid backend %q, must be one of: %s", b, strings.Join(names, ", "))
+}
+
+func (b Backend) extension() string {
+	if b == BackendExec {
+		if runtime.GOOS == "windows" {
+			return ".exe"
+		}
+		return ".bin"
+	}
+	return pluginExtension
+}
+
+// Program the compiled injected code, giving access to its exported functions.
+type Program interface {
+	// Lookup finds the exported function with the given name and stores it
+	// into "fn", which must be a pointer to a variable of the function's type.
+	// The last result of the function must be an error.
+	// The variable's type can have an additional leading "context.Context"
+	// parameter. The function then runs with the "ExecOptions" carried by
+	// this context (see "Execute()"). With the exec backend, it is cancelled
+	// with the context. With the plugin backend, it cannot be cancelled and
+	// the call always returns once the function is done.
+	Lookup(name string, fn interface{}) error
+}
+
+// loadError returned when the injected code could be built but not loaded.
+type loadError struct {
+	err error
+}
+
+func (e *loadError) Error() string {
+	return e.err.Error()
+}
+
+func (e *loadError) Unwrap() error {
+	return e.err
+}
+
+type pluginProgram struct {
+	plugin    *plugin.Plugin
+	directory string
+}
+
+func openPlugin(path string, directory string) (Program, error) {
+	plug, err := plugin.Open(path)
+	if err != nil {
+		return nil, &loadError{err}
+	}
+	return &pluginProgram{plugin: plug, directory: directory}, nil
+}
+
+func (p *pluginProgram) Lookup(name string, fn interface{}) error {
+	target, err := functionTarget(fn)
+	if err != nil {
+		return err
+	}
+	symbol, err := p.plugin.Lookup(name)
+	if err != nil {
+		return err
+	}
+	value := reflect.ValueOf(symbol)
+	t := target.Type()
+	if expected := withoutContext(t); !value.Type().AssignableTo(expected) {
+		return fmt.Errorf("injected function %q has type %s, expected %s", name, value.Type(), expected)
+	}
+	target.Set(reflect.MakeFunc(t, func(args []reflect.Value) []reflect.Value {
+		ctx, args := splitContext(t, args)
+		results, err := callInProcess(execOptions(ctx, p.directory), value, args)
+		if err != nil {
+			return errorResults(t, err)
+		}
+		return results
+	}))
+	return nil
+}
+
+// errorResults returns the zero values of the results of the given
+// function type, with the given error as last result.
+func errorResults(t reflect.Type, err error) []reflect.Value {
+	results := make([]reflect.Value, t.NumOut())
+	for i := range results {
+		results[i] = reflect.Zero(t.Out(i))
+	}
+	results[len(results)-1] = reflect.ValueOf(&err).Elem()
+	return results
+}
+
+// functionTarget checks "fn" is a pointer to a function variable whose
+// last result is an error, and returns the pointed value.
+func functionTarget(fn interface{}) (reflect.Value, error) {
+	value := reflect.ValueOf(fn)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Func {
+		return reflect.Value{}, errors.New("Lookup target must be a non-nil pointer to a function")
+	}
+	t := value.Elem().Type()
+	if t.NumOut() == 0 || t.Out(t.NumOut()-1) != errorType {
+		return reflect.Value{}, fmt.Errorf("Lookup target %s must return an error as last result", t)
+	}
+	return value.Elem(), nil
+}
+
+// pluginSupported returns true if Go plugins can be built and loaded
+// on the current platform with the current toolchain settings.
+func (i *Injector) pluginSupported() bool {
+	switch runtime.GOOS {
+	case "linux", "darwin", "freebsd":
+	default:
+		return false
+	}
+	out, err := i.goCommand("env", "CGO_ENABLED").Output()
+	return err == nil && strings.TrimSpace(string(out)) == "1"
+}
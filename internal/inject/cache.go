@@ -0,0 +1,197 @@
+package inject
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	cacheKeyVersion = "gyv-plugin-cache-v2"
+	pluginExtension = ".so"
+)
+
+var cacheExtensions = map[string]bool{pluginExtension: true, ".bin": true, ".exe": true}
+
+// NoCache disables the plugin build cache when true.
+var NoCache = false
+
+// Cache a directory storing compiled plugins and executables. Each plugin is identified by
+// a hash of everything its build depends on, so a cached plugin can be reused
+// as long as the project, its dependencies and the toolchain didn't change.
+type Cache struct {
+	Directory string
+}
+
+// CacheInfo statistics about the content of a plugin cache.
+type CacheInfo struct {
+	Directory string
+	Entries   int
+	Size      int64
+}
+
+// NewCache returns the plugin cache located in the user cache directory
+// (e.g.: "~/.cache/gyv/plugins" on Linux).
+func NewCache() (*Cache, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{Directory: filepath.Join(dir, "gyv", "plugins")}, nil
+}
+
+// Path returns the path to the cached plugin or executable identified
+// by the given key and file extension. The file may not exist.
+func (c *Cache) Path(key, extension string) string {
+	return filepath.Join(c.Directory, key+extension)
+}
+
+// Lookup returns the path to the cached plugin or executable identified
+// by the given key and file extension, and true if it exists.
+func (c *Cache) Lookup(key, extension string) (string, bool) {
+	path := c.Path(key, extension)
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return "", false
+	}
+	return path, true
+}
+
+// Info returns the number of cached plugins and executables and their total size.
+func (c *Cache) Info() (*CacheInfo, error) {
+	info := &CacheInfo{Directory: c.Directory}
+	entries, err := os.ReadDir(c.Directory)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return info, nil
+		}
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.IsDir() || !cacheExtensions[filepath.Ext(e.Name())] {
+			continue
+		}
+		fileInfo, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		info.Entries++
+		info.Size += fileInfo.Size()
+	}
+	return info, nil
+}
+
+// Clean removes all cached plugins and executables.
+func (c *Cache) Clean() error {
+	return os.RemoveAll(c.Directory)
+}
+
+// buildEnvironment the "go env" variables changing the output of a build.
+var buildEnvironment = []string{
+	"GOOS", "GOARCH", "GOARM", "GO386", "GOAMD64", "GOMIPS", "GOMIPS64", "GOPPC64", "GOWASM",
+	"GOEXPERIMENT", "CGO_ENABLED", "CC", "CXX", "CGO_CFLAGS", "CGO_CPPFLAGS", "CGO_CXXFLAGS",
+	"CGO_FFLAGS", "CGO_LDFLAGS", "PKG_CONFIG",
+}
+
+// cacheKey computes the cache key of the plugin or executable built by the given
+// backend from the given injected source file. The key depends on the backend,
+// the toolchain, the build options, the build environment ("go env"), the platform
+// gyv runs on, the injected source, the dependencies added for the injection, "go.mod",
+// "go.sum", the project's files and the files of the modules replaced by a local directory.
+func (i *Injector) cacheKey(backend Backend, source []byte) (string, error) {
+	goVersion, err := i.goCommand("version").Output()
+	if err != nil {
+		return "", err
+	}
+	goEnv, err := i.goCommand(append([]string{"env"}, buildEnvironment...)...).Output()
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	fmt.Fprintln(hash, cacheKeyVersion, backend)
+	fmt.Fprintln(hash, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintln(hash, strings.TrimSpace(string(goVersion)))
+	fmt.Fprintln(hash, strings.Join(strings.Fields(string(goEnv)), " "))
+	fmt.Fprintln(hash, i.buildFlags(), i.BuildOptions.GoFlags, os.Getenv("GOFLAGS"))
+	for _, d := range i.Dependencies {
+		fmt.Fprintln(hash, d.Name, d.Version)
+	}
+	fmt.Fprintf(hash, "%d\n", len(source))
+	hash.Write(source)
+
+	if err := hashModule(hash, i.directory, ""); err != nil {
+		return "", err
+	}
+	if i.ModFile != nil {
+		for _, r := range i.ModFile.Replace {
+			if r.New.Version != "" {
+				continue // Not a local directory
+			}
+			directory := r.New.Path
+			if !filepath.IsAbs(directory) {
+				directory = filepath.Join(i.directory, directory)
+			}
+			fmt.Fprintln(hash, "replace", r.Old.Path, r.Old.Version, r.New.Path)
+			if err := hashModule(hash, directory, r.New.Path+"/"); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// hashModule writes the hash of every file of the module in the given directory
+// to the given writer, except test files, hidden files and nested modules.
+// Non-Go files are included because they can be embedded in the binary.
+// The paths of the files are relative to the module and prefixed with the given prefix.
+func hashModule(w io.Writer, directory, prefix string) error {
+	return filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name := info.Name()
+		if info.IsDir() {
+			if path == directory {
+				return nil
+			}
+			if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir // Nested module
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+			strings.HasSuffix(name, "_test.go") || strings.HasPrefix(name, "codeinject-") {
+			return nil
+		}
+		return hashFile(w, directory, path, prefix)
+	})
+}
+
+func hashFile(w io.Writer, root, path, prefix string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return err
+	}
+	fileHash := sha256.New()
+	if _, err := io.Copy(fileHash, file); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s%s %x\n", prefix, filepath.ToSlash(rel), fileHash.Sum(nil))
+	return nil
+}
@@ -0,0 +1,126 @@
+package inject
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mod/modfile"
+)
+
+func TestCache(t *testing.T) {
+	assert := assert.New(t)
+	cache := &Cache{Directory: filepath.Join(t.TempDir(), "plugins")}
+
+	info, err := cache.Info()
+	assert.Nil(err)
+	assert.Equal(0, info.Entries)
+
+	_, ok := cache.Lookup("abc", pluginExtension)
+	assert.False(ok)
+
+	writeTestFile(t, cache.Path("abc", pluginExtension), "plugin")
+	writeTestFile(t, cache.Path("def", BackendExec.extension()), "exec")
+	writeTestFile(t, filepath.Join(cache.Directory, "other.txt"), "ignored")
+
+	path, ok := cache.Lookup("abc", pluginExtension)
+	assert.True(ok)
+	assert.Equal(filepath.Join(cache.Directory, "abc.so"), path)
+
+	info, err = cache.Info()
+	assert.Nil(err)
+	assert.Equal(2, info.Entries)
+	assert.Equal(int64(10), info.Size)
+
+	assert.Nil(cache.Clean())
+	_, ok = cache.Lookup("abc", pluginExtension)
+	assert.False(ok)
+}
+
+func TestCacheKey(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example\n")
+	writeTestFile(t, filepath.Join(dir, "main.go"), "package main\n")
+	injector := &Injector{directory: dir}
+
+	key, err := injector.cacheKey(BackendPlugin, []byte("source"))
+	if err != nil {
+		t.Skip(err) // Go toolchain not available
+	}
+
+	writeTestFile(t, filepath.Join(dir, "main_test.go"), "package main\n")
+	writeTestFile(t, filepath.Join(dir, ".git", "HEAD"), "ref")
+	same, err := injector.cacheKey(BackendPlugin, []byte("source"))
+	assert.Nil(err)
+	assert.Equal(key, same)
+
+	other, err := injector.cacheKey(BackendPlugin, []byte("other source"))
+	assert.Nil(err)
+	assert.NotEqual(key, other)
+
+	exec, err := injector.cacheKey(BackendExec, []byte("source"))
+	assert.Nil(err)
+	assert.NotEqual(key, exec)
+
+	injector.BuildOptions.Tags = []string{"sqlite"}
+	tags, err := injector.cacheKey(BackendPlugin, []byte("source"))
+	assert.Nil(err)
+	assert.NotEqual(key, tags)
+	injector.BuildOptions.Tags = nil
+
+	writeTestFile(t, filepath.Join(dir, "http", "route.go"), "package http\n")
+	changed, err := injector.cacheKey(BackendPlugin, []byte("source"))
+	assert.Nil(err)
+	assert.NotEqual(key, changed)
+
+	// Embedded assets
+	writeTestFile(t, filepath.Join(dir, "resources", "lang", "en-US", "fields.json"), "{}")
+	embedded, err := injector.cacheKey(BackendPlugin, []byte("source"))
+	assert.Nil(err)
+	assert.NotEqual(changed, embedded)
+
+	// Build environment
+	previous, ok := os.LookupEnv("GOOS")
+	if err := os.Setenv("GOOS", "windows"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if ok {
+			_ = os.Setenv("GOOS", previous)
+		} else {
+			_ = os.Unsetenv("GOOS")
+		}
+	}()
+	goos, err := injector.cacheKey(BackendPlugin, []byte("source"))
+	assert.Nil(err)
+	assert.NotEqual(embedded, goos)
+}
+
+func TestCacheKeyReplace(t *testing.T) {
+	assert := assert.New(t)
+	root := t.TempDir()
+	dir := filepath.Join(root, "project")
+	lib := filepath.Join(root, "lib")
+	goMod := "module example\n\nrequire example.com/lib v1.0.0\n\nreplace example.com/lib => ../lib\n"
+	writeTestFile(t, filepath.Join(dir, "go.mod"), goMod)
+	writeTestFile(t, filepath.Join(dir, "main.go"), "package main\n")
+	writeTestFile(t, filepath.Join(lib, "go.mod"), "module example.com/lib\n")
+	writeTestFile(t, filepath.Join(lib, "lib.go"), "package lib\n")
+	modFile, err := modfile.Parse("go.mod", []byte(goMod), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	injector := &Injector{directory: dir, ModFile: modFile}
+
+	key, err := injector.cacheKey(BackendPlugin, []byte("source"))
+	if err != nil {
+		t.Skip(err) // Go toolchain not available
+	}
+
+	writeTestFile(t, filepath.Join(lib, "lib.go"), "package lib\n\nfunc Lib() {}\n")
+	changed, err := injector.cacheKey(BackendPlugin, []byte("source"))
+	assert.Nil(err)
+	assert.NotEqual(key, changed)
+}
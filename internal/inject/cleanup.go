@@ -0,0 +1,72 @@
+package inject
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+var (
+	temporaryFiles   = []string{}
+	temporaryFilesMu sync.Mutex
+)
+
+func registerTemporaryFile(path string) {
+	temporaryFilesMu.Lock()
+	defer temporaryFilesMu.Unlock()
+	temporaryFiles = append(temporaryFiles, path)
+}
+
+func unregisterTemporaryFile(path string) {
+	temporaryFilesMu.Lock()
+	defer temporaryFilesMu.Unlock()
+	for i, f := range temporaryFiles {
+		if f == path {
+			temporaryFiles = append(temporaryFiles[:i], temporaryFiles[i+1:]...)
+			return
+		}
+	}
+}
+
+// removeTemporaryFile removes the given registered temporary file or directory
+// and unregisters it.
+func removeTemporaryFile(path string) error {
+	unregisterTemporaryFile(path)
+	return os.RemoveAll(path)
+}
+
+// Cleanup removes the temporary files and directories that have not been
+// removed yet, such as the build workspaces of interrupted injections or
+// the executables built by the exec backend when the cache is disabled.
+// Should be called before gyv exits.
+func Cleanup() {
+	temporaryFilesMu.Lock()
+	defer temporaryFilesMu.Unlock()
+	for _, f := range temporaryFiles {
+		_ = os.RemoveAll(f)
+	}
+	temporaryFiles = temporaryFiles[:0]
+}
+
+// CleanupOnSignal makes gyv call "Cleanup()" and exit when it receives
+// SIGINT or SIGTERM, so an interrupted injection doesn't leave files behind.
+// If operations are running with "Execute()", they are cancelled instead
+// and gyv only exits if it receives a second signal.
+func CleanupOnSignal() {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		interrupt()
+		if atomic.LoadInt32(&running) > 0 {
+			sig = <-signals
+		}
+		Cleanup()
+		if sig == syscall.SIGTERM {
+			os.Exit(143)
+		}
+		os.Exit(130)
+	}()
+}
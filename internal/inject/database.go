@@ -0,0 +1,181 @@
+package inject
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DatabaseLayout the directories, relative to the project root,
+// containing the models and the seeders of a Goyave project.
+// Sub-packages of these directories are discovered recursively.
+type DatabaseLayout struct {
+	ModelRoot  string
+	SeederRoot string
+}
+
+// DefaultDatabaseLayout the database layout of the Goyave project template.
+var DefaultDatabaseLayout = DatabaseLayout{
+	ModelRoot:  "database/model",
+	SeederRoot: "database/seeder",
+}
+
+// SeederFunction an exported seeder function and the package it belongs to.
+type SeederFunction struct {
+	// Package the path of the package, starting with the name of the seeders root
+	// directory (e.g.: "seeder" or "seeder/billing").
+	Package    string
+	ImportPath string
+	Function   string
+}
+
+// String returns the seeder function qualified by its package (e.g.: "seeder/billing.Invoices").
+func (s *SeederFunction) String() string {
+	return s.Package + "." + s.Function
+}
+
+func modelPackageInputs(importPaths []string) []*Input {
+	inputs := make([]*Input, 0, len(importPaths))
+	for _, p := range importPaths {
+		inputs = append(inputs, &Input{
+			Name:  "model package",
+			Value: p,
+			Hint:  "All packages under the model root are imported: make sure this package compiles, is not a main package, or use --model-root to select another directory.",
+		})
+	}
+	return inputs
+}
+
+func migrationPackageInput(importPath, root string) *Input {
+	return &Input{
+		Name:  "migration package",
+		Value: importPath,
+		Hint:  fmt.Sprintf("Versioned migrations must be registered in %q using the registry generated by \"gyv create migration\".", root),
+	}
+}
+
+// FindPackages recursively find all directories containing Go source files
+// (excluding tests) inside the given directory. The returned paths are
+// slash-separated and relative to the given directory ("." for the directory itself).
+// Like the Go tool, directories starting with "." or "_" and "testdata" directories are ignored.
+// "vendor" directories and nested modules are ignored as well.
+// If the given directory doesn't exist, an empty slice is returned.
+func FindPackages(directory string) ([]string, error) {
+	packages := []string{}
+	found := map[string]bool{}
+	err := filepath.Walk(directory, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == directory {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if info.IsDir() {
+			if p == directory {
+				return nil
+			}
+			name := info.Name()
+			if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor" {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(p, "go.mod")); err == nil {
+				return filepath.SkipDir // Nested module
+			}
+			return nil
+		}
+		if filepath.Ext(p) != ".go" || strings.HasSuffix(p, "_test.go") {
+			return nil
+		}
+		rel, err := filepath.Rel(directory, filepath.Dir(p))
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !found[rel] {
+			found[rel] = true
+			packages = append(packages, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(packages)
+	return packages, nil
+}
+
+// FindPackageImportPaths returns the import paths of all the packages found
+// recursively in the given root directory (relative to the project directory).
+func FindPackageImportPaths(projectDirectory, modulePath, root string) ([]string, error) {
+	packages, err := FindPackages(filepath.Join(projectDirectory, filepath.FromSlash(root)))
+	if err != nil {
+		return nil, err
+	}
+	importPaths := make([]string, 0, len(packages))
+	for _, p := range packages {
+		importPaths = append(importPaths, path.Join(modulePath, root, p))
+	}
+	return importPaths, nil
+}
+
+// FindSeeders recursively find all seeder functions in the given root directory
+// (relative to the project directory). A seeder is an exported function without
+// parameters nor return values. The seeders of the root package come first,
+// and "Run" comes first in its package.
+func FindSeeders(projectDirectory, modulePath, root string) ([]*SeederFunction, error) {
+	rootDirectory := filepath.Join(projectDirectory, filepath.FromSlash(root))
+	packages, err := FindPackages(rootDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	seeders := []*SeederFunction{}
+	for _, p := range packages {
+		functions, err := findExportedFunctionsInPackage(filepath.Join(rootDirectory, filepath.FromSlash(p)))
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(functions, func(i, j int) bool {
+			return functions[i] == "Run" && functions[j] != "Run"
+		})
+		for _, f := range functions {
+			seeders = append(seeders, &SeederFunction{
+				Package:    path.Join(path.Base(root), p),
+				ImportPath: path.Join(modulePath, root, p),
+				Function:   f,
+			})
+		}
+	}
+	return seeders, nil
+}
+
+func findExportedFunctionsInPackage(directory string) ([]string, error) {
+	files, err := findGoFiles(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	functions := []string{}
+	for _, f := range files {
+		if strings.HasSuffix(f, "_test.go") {
+			continue
+		}
+		astFile, err := parser.ParseFile(token.NewFileSet(), f, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range astFile.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if ok && fn.Recv == nil && fn.Type.Results == nil && fn.Type.Params.List == nil && fn.Name.IsExported() {
+				functions = append(functions, fn.Name.Name)
+			}
+		}
+	}
+	return functions, nil
+}
@@ -0,0 +1,71 @@
+package inject
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestFile(t *testing.T, path string, content string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0744); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindPackages(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "model.go"), "package model\n")
+	writeTestFile(t, filepath.Join(dir, "model_test.go"), "package model\n")
+	writeTestFile(t, filepath.Join(dir, "billing", "invoice.go"), "package billing\n")
+	writeTestFile(t, filepath.Join(dir, "billing", "tax", "tax.go"), "package tax\n")
+	writeTestFile(t, filepath.Join(dir, "testonly", "a_test.go"), "package testonly\n")
+	writeTestFile(t, filepath.Join(dir, "testdata", "fixture.go"), "package testdata\n")
+	writeTestFile(t, filepath.Join(dir, ".hidden", "hidden.go"), "package hidden\n")
+	writeTestFile(t, filepath.Join(dir, "empty", "README.md"), "")
+
+	packages, err := FindPackages(dir)
+	assert.Nil(err)
+	assert.Equal([]string{".", "billing", "billing/tax"}, packages)
+
+	packages, err = FindPackages(filepath.Join(dir, "notadir"))
+	assert.Nil(err)
+	assert.Empty(packages)
+}
+
+func TestFindSeeders(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "database", "seeder", "seeder.go"), `package seeder
+
+func User() {}
+func Run() {}
+func helper() {}
+func WithParam(n int) {}
+`)
+	writeTestFile(t, filepath.Join(dir, "database", "seeder", "billing", "billing.go"), `package billing
+
+func Invoices() {}
+func Run() {}
+`)
+
+	seeders, err := FindSeeders(dir, "example.com/shop", "database/seeder")
+	assert.Nil(err)
+
+	names := []string{}
+	for _, s := range seeders {
+		names = append(names, s.String())
+	}
+	assert.Equal([]string{"seeder.Run", "seeder.User", "seeder/billing.Run", "seeder/billing.Invoices"}, names)
+	assert.Equal("example.com/shop/database/seeder", seeders[0].ImportPath)
+	assert.Equal("example.com/shop/database/seeder/billing", seeders[2].ImportPath)
+
+	importPaths, err := FindPackageImportPaths(dir, "example.com/shop", "database/seeder")
+	assert.Nil(err)
+	assert.Equal([]string{"example.com/shop/database/seeder", "example.com/shop/database/seeder/billing"}, importPaths)
+}
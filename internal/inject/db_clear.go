@@ -1,30 +1,40 @@
 package inject
 
-import "goyave.dev/gyv/internal/stub"
+import (
+	"context"
+
+	"goyave.dev/gyv/internal/stub"
+)
 
 // DBClear generate and return database clear function.
-func DBClear(directory string) (func() error, error) {
+// If "models" is not empty, only the records of the registered models
+// having these names (e.g.: "User") are deleted.
+func DBClear(directory string, layout DatabaseLayout, models []string) (func(ctx context.Context) error, error) {
 	injector, err := NewInjector(directory)
 	if err != nil {
 		return nil, err
 	}
 
-	modelImportPath := injector.ModFile.Module.Mod.Path + "/database/model"
-	// TODO what if there are sub-directories for models? Blank import them recursively
+	modelImportPaths, err := FindPackageImportPaths(directory, injector.ModFile.Module.Mod.Path, layout.ModelRoot)
+	if err != nil {
+		return nil, err
+	}
 
+	injector.Inputs = append(injector.Inputs, modelPackageInputs(modelImportPaths)...)
 	injector.StubName = stub.InjectDBClear
 
 	injector.StubData = stub.Data{
-		"ModelImportPath": modelImportPath,
+		"ModelImportPaths": modelImportPaths,
+		"Models":           models,
 	}
 
-	plug, err := injector.Inject()
+	program, err := injector.Inject()
 	if err != nil {
 		return nil, err
 	}
-	s, err := plug.Lookup("DBClear")
-	if err != nil {
+	var dBClear func(context.Context) error
+	if err := program.Lookup("DBClear", &dBClear); err != nil {
 		return nil, err
 	}
-	return s.(func() error), nil
+	return dBClear, nil
 }
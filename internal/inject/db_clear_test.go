@@ -0,0 +1,69 @@
+package inject
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/gyv/internal/stub"
+)
+
+func TestDBClearAllowedModels(t *testing.T) {
+	cases := []struct {
+		desc   string
+		models []string
+	}{
+		{desc: "all models", models: nil},
+		{desc: "single model", models: []string{"User"}},
+		{desc: "multiple models", models: []string{"User", "Product"}},
+		{desc: "quoted model", models: []string{`User"}; panic("injected`}},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			source, err := stub.Load(stub.InjectDBClear, stub.Data{
+				"GoyaveImportPath": "goyave.dev/goyave/v4",
+				"ModelImportPaths": []string{"example/database/model"},
+				"Models":           c.models,
+			})
+			if !assert.Nil(err) {
+				return
+			}
+			file, err := parser.ParseFile(token.NewFileSet(), "", source.Bytes(), 0)
+			if !assert.Nil(err) {
+				return
+			}
+
+			var allowed *ast.CompositeLit
+			ast.Inspect(file, func(node ast.Node) bool {
+				if assign, ok := node.(*ast.AssignStmt); ok {
+					if ident, ok := assign.Lhs[0].(*ast.Ident); ok && ident.Name == "allowed" {
+						allowed, _ = assign.Rhs[0].(*ast.CompositeLit)
+					}
+				}
+				return allowed == nil
+			})
+			if !assert.NotNil(allowed) {
+				return
+			}
+
+			names := []string{}
+			for _, element := range allowed.Elts {
+				key := element.(*ast.KeyValueExpr).Key.(*ast.BasicLit)
+				name, err := strconv.Unquote(key.Value)
+				assert.Nil(err)
+				names = append(names, name)
+			}
+			expected := c.models
+			if expected == nil {
+				expected = []string{}
+			}
+			assert.Equal(expected, names)
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package inject
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// KeepTemp keeps the generated source files when the injected build fails.
+var KeepTemp = false
+
+var diagnosticRegex = regexp.MustCompile(`^(.+?\.go):(\d+)(?::(\d+))?: (.*)$`)
+
+// Input a value detected in the project and used to generate the injected code,
+// such as the route registrar or a seeder function.
+type Input struct {
+	// Name a description of the input (e.g.: "route registrar")
+	Name string
+
+	// Value the code of the input as it appears in the generated file (e.g.: "route.Register")
+	Value string
+
+	// Origin where the input comes from (e.g.: the import path of its package)
+	Origin string
+
+	// Hint suggests a fix when a compilation error involves this input.
+	Hint string
+}
+
+// Diagnostic a compilation error reported by the go command.
+type Diagnostic struct {
+	// File the path to the file containing the error, relative to the project's
+	// root. Empty if the message is not attached to a file.
+	File    string
+	Line    int
+	Column  int
+	Message string
+
+	// Generated true if the error is located in the generated source file.
+	Generated bool
+
+	// Source the line of generated code containing the error.
+	Source string
+
+	// Input the detected input involved in the error, if any.
+	Input *Input
+
+	// Suggestion a likely fix for the error.
+	Suggestion string
+}
+
+// BuildError returned when the injected code cannot be built.
+type BuildError struct {
+	// Stub the path to the stub the injected code was generated from.
+	Stub        string
+	Diagnostics []*Diagnostic
+
+	// Output the raw output of the go command.
+	Output string
+
+	// KeptDirectory the directory containing the generated files
+	// if they were kept (see "KeepTemp").
+	KeptDirectory string
+
+	err error
+}
+
+func (e *BuildError) Error() string {
+	builder := &strings.Builder{}
+	fmt.Fprintf(builder, "Could not build the code injected into the project (%s):", e.err)
+	for _, d := range e.Diagnostics {
+		builder.WriteString("\n")
+		switch {
+		case d.Generated:
+			fmt.Fprintf(builder, "  • generated file %s, line %d (from stub %s): %s\n", filepath.Base(d.File), d.Line, e.Stub, d.Message)
+			if d.Source != "" {
+				fmt.Fprintf(builder, "      %s\n", d.Source)
+			}
+		case d.File != "":
+			fmt.Fprintf(builder, "  • %s:%d:%d: %s\n", d.File, d.Line, d.Column, d.Message)
+		default:
+			fmt.Fprintf(builder, "  • %s\n", d.Message)
+		}
+		if d.Input != nil {
+			fmt.Fprintf(builder, "    Detected %s: %s", d.Input.Name, d.Input.Value)
+			if d.Input.Origin != "" {
+				fmt.Fprintf(builder, " (%s)", d.Input.Origin)
+			}
+			builder.WriteString("\n")
+		}
+		if d.Suggestion != "" {
+			fmt.Fprintf(builder, "    💡 %s\n", d.Suggestion)
+		}
+	}
+	if len(e.Diagnostics) == 0 && e.Output != "" {
+		fmt.Fprintf(builder, "\n%s\n", strings.TrimSpace(e.Output))
+	}
+	if e.KeptDirectory != "" {
+		fmt.Fprintf(builder, "\n📝 Generated files kept in %s", e.KeptDirectory)
+	} else {
+		builder.WriteString("\nUse --keep-temp to keep the generated files.")
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+func (e *BuildError) Unwrap() error {
+	return e.err
+}
+
+// newBuildError parses the output of the go command. "generated" associates the
+// names of the generated files with their content.
+func (i *Injector) newBuildError(err error, output string, generated map[string][]byte) *BuildError {
+	buildErr := &BuildError{
+		Stub:   i.StubName,
+		Output: output,
+		err:    err,
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		d := &Diagnostic{Message: line}
+		if matches := diagnosticRegex.FindStringSubmatch(line); matches != nil {
+			d.File = i.relativePath(matches[1])
+			d.Line, _ = strconv.Atoi(matches[2])
+			d.Column, _ = strconv.Atoi(matches[3])
+			d.Message = matches[4]
+			if source, ok := generated[filepath.Base(d.File)]; ok {
+				d.Generated = true
+				d.Source = sourceLine(source, d.Line)
+				d.Input = i.findInput(d.Source, d.Message)
+			}
+		}
+		d.Suggestion = suggestFix(d, i.BuildOptions.Tags)
+		buildErr.Diagnostics = append(buildErr.Diagnostics, d)
+	}
+	return buildErr
+}
+
+func (i *Injector) relativePath(path string) string {
+	if !filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	directory, err := filepath.Abs(i.directory)
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(directory, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// findInput returns the input appearing in the given line of generated code or
+// in the error message. The longest match is preferred so "seeder1.RunAll" is not
+// mistaken for "seeder1.Run".
+func (i *Injector) findInput(source, message string) *Input {
+	var found *Input
+	for _, input := range i.Inputs {
+		if input.Value == "" || (!strings.Contains(source, input.Value) && !strings.Contains(message, input.Value)) {
+			continue
+		}
+		if found == nil || len(input.Value) > len(found.Value) {
+			found = input
+		}
+	}
+	return found
+}
+
+func sourceLine(source []byte, line int) string {
+	lines := strings.Split(string(source), "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return strings.TrimSpace(lines[line-1])
+}
+
+func suggestFix(d *Diagnostic, tags []string) string {
+	message := d.Message
+	switch {
+	case strings.Contains(message, "-buildmode=plugin"):
+		return "Go plugins are not supported in this environment, use --backend exec."
+	case strings.Contains(message, "build constraints exclude all Go files"):
+		if len(tags) == 0 {
+			return "The package requires build tags, set them with --tags or in the \"build\" section of gyv.json."
+		}
+		return fmt.Sprintf("Check the build tags (currently %q).", strings.Join(tags, ","))
+	case strings.Contains(message, "no required module provides package"),
+		strings.Contains(message, "is not in std"),
+		strings.Contains(message, "cannot find package"),
+		strings.Contains(message, "could not import"):
+		if d.Input != nil && d.Input.Hint != "" {
+			return d.Input.Hint
+		}
+		return "Check the import path and run \"go mod tidy\" in the project."
+	case strings.Contains(message, "inconsistent vendoring"):
+		return "Run \"go mod vendor\" in the project."
+	}
+
+	if d.Input != nil && d.Input.Hint != "" {
+		return d.Input.Hint
+	}
+	if !d.Generated && d.File != "" {
+		return "The project doesn't compile, fix this error first."
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package inject
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/gyv/internal/stub"
+)
+
+func TestNewBuildError(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	injector := &Injector{directory: dir, StubName: stub.InjectSeeder}
+	registrar := &Input{Name: "seeder", Value: "seeder0.Run", Origin: "example/database/seeder.Run", Hint: "seeder hint"}
+	longer := &Input{Name: "seeder", Value: "seeder0.RunAll", Origin: "example/database/seeder.RunAll", Hint: "other hint"}
+	injector.Inputs = []*Input{registrar, longer}
+
+	source := []byte("package main\n\nfunc Seed() error {\n\tseeder0.Run()\n\tseeder0.RunAll(1)\n\treturn nil\n}\n")
+	output := "# example\n" +
+		"./codeinject-1.go:4:2: undefined: seeder0.Run\n" +
+		filepath.Join(dir, "codeinject-1.go") + ":5:17: too many arguments in call to seeder0.RunAll\n" +
+		filepath.Join(dir, "http", "route.go") + ":12:3: undefined: controller\n" +
+		"package example/missing is not in std\n"
+
+	buildErr := injector.newBuildError(errors.New("exit status 1"), output, map[string][]byte{"codeinject-1.go": source})
+	assert.Equal(stub.InjectSeeder, buildErr.Stub)
+	assert.Len(buildErr.Diagnostics, 4)
+
+	d := buildErr.Diagnostics[0]
+	assert.True(d.Generated)
+	assert.Equal("codeinject-1.go", d.File)
+	assert.Equal(4, d.Line)
+	assert.Equal(2, d.Column)
+	assert.Equal("undefined: seeder0.Run", d.Message)
+	assert.Equal("seeder0.Run()", d.Source)
+	assert.Equal(registrar, d.Input)
+	assert.Equal("seeder hint", d.Suggestion)
+
+	d = buildErr.Diagnostics[1]
+	assert.True(d.Generated)
+	assert.Equal("seeder0.RunAll(1)", d.Source)
+	assert.Equal(longer, d.Input)
+
+	d = buildErr.Diagnostics[2]
+	assert.False(d.Generated)
+	assert.Equal(filepath.Join("http", "route.go"), d.File)
+	assert.Nil(d.Input)
+	assert.Equal("The project doesn't compile, fix this error first.", d.Suggestion)
+
+	d = buildErr.Diagnostics[3]
+	assert.Empty(d.File)
+	assert.Equal("Check the import path and run \"go mod tidy\" in the project.", d.Suggestion)
+
+	message := buildErr.Error()
+	assert.Contains(message, "generated file codeinject-1.go, line 4 (from stub "+stub.InjectSeeder+"): undefined: seeder0.Run")
+	assert.Contains(message, "Detected seeder: seeder0.Run (example/database/seeder.Run)")
+	assert.Contains(message, "💡 seeder hint")
+	assert.Contains(message, "--keep-temp")
+}
+
+func TestBuildErrorKeepTemp(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("Go toolchain not available")
+	}
+	assert := assert.New(t)
+	KeepTemp = true
+	defer func() { KeepTemp = false }()
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example\n\ngo 1.16\n")
+	writeTestFile(t, filepath.Join(dir, "main.go"), "package main\n\nfunc main() {}\n")
+	injector := &Injector{
+		directory: dir,
+		StubName:  stub.InjectRouteList,
+		Stdout:    io.Discard,
+		Inputs:    []*Input{{Name: "route registrar", Value: "route.Register", Hint: "registrar hint"}},
+	}
+
+	source := []byte("package main\n\nfunc ListRoutes() ([]byte, error) {\n\troute.Register(nil)\n\treturn nil, nil\n}\n")
+	err := injector.build(filepath.Join(t.TempDir(), "program"), BackendExec, source)
+	buildErr := &BuildError{}
+	if !assert.True(errors.As(err, &buildErr)) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(buildErr.KeptDirectory) }()
+
+	assert.Len(buildErr.Diagnostics, 1)
+	assert.True(buildErr.Diagnostics[0].Generated)
+	assert.Equal("route.Register(nil)", buildErr.Diagnostics[0].Source)
+	assert.Equal("registrar hint", buildErr.Diagnostics[0].Suggestion)
+	assert.NotEmpty(buildErr.KeptDirectory)
+	assert.DirExists(buildErr.KeptDirectory)
+	assert.Contains(buildErr.Error(), "Generated files kept in "+buildErr.KeptDirectory)
+}
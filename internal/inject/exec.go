@@ -0,0 +1,159 @@
+package inject
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"reflect"
+
+	"goyave.dev/gyv/internal/stub"
+)
+
+// execEnvVariable the environment variable telling an executable built
+// by the exec backend to handle a request instead of running the project.
+const execEnvVariable = "GYV_INJECT_EXEC"
+
+// execRequest the request written on the standard input of the executable.
+// Each argument is encoded in JSON.
+type execRequest struct {
+	Function string        `json:"function"`
+	Args     []interface{} `json:"args"`
+}
+
+// execResponse the response written on the standard output of the executable.
+// "Results" contains all the results of the function except the error,
+// which is reported in "Error" if not nil.
+type execResponse struct {
+	Results []json.RawMessage `json:"results"`
+	Error   *string           `json:"error"`
+}
+
+type execProgram struct {
+	path      string
+	directory string
+	functions []string
+
+	// stderr receives the standard error of the executable
+	// and the unexpected lines written on its standard output.
+	stderr io.Writer
+}
+
+func (p *execProgram) Lookup(name string, fn interface{}) error {
+	target, err := functionTarget(fn)
+	if err != nil {
+		return err
+	}
+	if !contains(p.functions, name) {
+		return fmt.Errorf("injected function %q not found", name)
+	}
+
+	t := target.Type()
+	target.Set(reflect.MakeFunc(t, func(args []reflect.Value) []reflect.Value {
+		ctx, args := splitContext(t, args)
+		results := make([]reflect.Value, t.NumOut())
+		for i := range results {
+			results[i] = reflect.Zero(t.Out(i))
+		}
+		if err := p.call(ctx, name, args, results); err != nil {
+			return errorResults(t, err)
+		}
+		return results
+	}))
+	return nil
+}
+
+func (p *execProgram) call(ctx context.Context, name string, args []reflect.Value, results []reflect.Value) error {
+	request := execRequest{Function: name, Args: make([]interface{}, 0, len(args))}
+	for _, a := range args {
+		request.Args = append(request.Args, a.Interface())
+	}
+	input, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	options := execOptions(ctx, p.directory)
+	stdout := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, p.path)
+	cmd.Dir = options.Directory
+	cmd.Env = append(os.Environ(), execEnvVariable+"=1")
+	for k, v := range options.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = stdout
+	cmd.Stderr = p.stderr
+	runErr := cmd.Run()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	// The response is the last line of the output. Anything written before
+	// (by package initializers for example) is forwarded to stderr.
+	output := bytes.TrimSpace(stdout.Bytes())
+	if i := bytes.LastIndexByte(output, '\n'); i != -1 {
+		_, _ = p.stderr.Write(output[:i+1])
+		output = output[i+1:]
+	}
+	response := &execResponse{}
+	if err := json.Unmarshal(output, response); err != nil {
+		if runErr != nil {
+			return fmt.Errorf("Injected program failed: %w", runErr)
+		}
+		return fmt.Errorf("Injected program returned an invalid response: %w", err)
+	}
+
+	if response.Error != nil {
+		return errors.New(*response.Error)
+	}
+	if len(response.Results) != len(results)-1 {
+		return fmt.Errorf("Injected function %q returned %d results, expected %d", name, len(response.Results), len(results)-1)
+	}
+	for i, raw := range response.Results {
+		value := reflect.New(results[i].Type())
+		if err := json.Unmarshal(raw, value.Interface()); err != nil {
+			return err
+		}
+		results[i] = value.Elem()
+	}
+	return nil
+}
+
+// renderExecMain renders the file making the executable built from the given
+// injected source handle requests for all its exported functions.
+func renderExecMain(source []byte) ([]byte, error) {
+	functions, err := findExportedFunctionsInSource(source)
+	if err != nil {
+		return nil, err
+	}
+	s, err := stub.Load(stub.InjectExecMain, stub.Data{
+		"EnvVariable": execEnvVariable,
+		"Functions":   functions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.Bytes(), nil
+}
+
+func findExportedFunctionsInSource(source []byte) ([]string, error) {
+	astFile, err := parser.ParseFile(token.NewFileSet(), "", source, 0)
+	if err != nil {
+		return nil, err
+	}
+	functions := []string{}
+	for _, decl := range astFile.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.IsExported() {
+			functions = append(functions, fn.Name.Name)
+		}
+	}
+	return functions, nil
+}
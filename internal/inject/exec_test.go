@@ -0,0 +1,145 @@
+package inject
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const execTestSource = `package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+func Add(a, b int) (int, error) {
+	fmt.Println("this is not part of the response")
+	return a + b, nil
+}
+
+func Names(prefix string) ([]string, []byte, error) {
+	return []string{prefix + "a", prefix + "b"}, []byte("raw"), nil
+}
+
+func Fail() error {
+	return errors.New("failure")
+}
+
+func Panic() error {
+	panic("panic reason")
+}
+
+func Exit() error {
+	os.Exit(3)
+	return nil
+}
+
+func Env() (string, string, error) {
+	wd, err := os.Getwd()
+	return os.Getenv("GYV_TEST"), wd, err
+}
+
+func Sleep() error {
+	time.Sleep(10 * time.Second)
+	return nil
+}
+
+func unexported() error {
+	return nil
+}
+`
+
+func TestFindExportedFunctionsInSource(t *testing.T) {
+	functions, err := findExportedFunctionsInSource([]byte(execTestSource))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Add", "Names", "Fail", "Panic", "Exit", "Env", "Sleep"}, functions)
+}
+
+func TestExecBackend(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("Go toolchain not available")
+	}
+	assert := assert.New(t)
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example\n\ngo 1.16\n")
+	writeTestFile(t, filepath.Join(dir, "main.go"), "package main\n\nfunc main() {\n\tpanic(\"the project should not run\")\n}\n")
+
+	injector := &Injector{directory: dir, Stdout: io.Discard}
+	output := filepath.Join(t.TempDir(), "program"+BackendExec.extension())
+	source := []byte(execTestSource)
+	if !assert.Nil(injector.build(output, BackendExec, source)) {
+		return
+	}
+	entries, err := os.ReadDir(dir)
+	assert.Nil(err)
+	assert.Len(entries, 2) // The project is not modified
+
+	program, err := injector.open(output, BackendExec, source)
+	if !assert.Nil(err) {
+		return
+	}
+	program.(*execProgram).stderr = io.Discard
+
+	var add func(int, int) (int, error)
+	assert.Nil(program.Lookup("Add", &add))
+	sum, err := add(1, 2)
+	assert.Nil(err)
+	assert.Equal(3, sum)
+
+	var names func(string) ([]string, []byte, error)
+	assert.Nil(program.Lookup("Names", &names))
+	n, raw, err := names("x")
+	assert.Nil(err)
+	assert.Equal([]string{"xa", "xb"}, n)
+	assert.Equal([]byte("raw"), raw)
+
+	var fail func() error
+	assert.Nil(program.Lookup("Fail", &fail))
+	assert.EqualError(fail(), "failure")
+
+	var panicking func() error
+	assert.Nil(program.Lookup("Panic", &panicking))
+	assert.EqualError(panicking(), "panic reason")
+
+	var exit func() error
+	assert.Nil(program.Lookup("Exit", &exit))
+	assert.EqualError(exit(), "Injected program failed: exit status 3")
+
+	var env func(context.Context) (string, string, error)
+	assert.Nil(program.Lookup("Env", &env))
+	workingDir := t.TempDir()
+	options := ExecOptions{Directory: workingDir, Env: map[string]string{"GYV_TEST": "value"}}
+	result := Execute(context.Background(), options, func(ctx context.Context) error {
+		value, wd, err := env(ctx)
+		assert.Equal("value", value)
+		assert.Equal(workingDir, wd)
+		return err
+	})
+	assert.Nil(result.Err)
+	value, wd, err := env(context.Background())
+	assert.Nil(err)
+	assert.Empty(value)
+	assert.Equal(dir, wd)
+
+	var sleep func(context.Context) error
+	assert.Nil(program.Lookup("Sleep", &sleep))
+	result = Execute(context.Background(), ExecOptions{Timeout: 100 * time.Millisecond}, func(ctx context.Context) error {
+		return sleep(ctx)
+	})
+	assert.Equal(context.DeadlineExceeded, result.Err)
+	assert.Less(int64(result.Duration), int64(5*time.Second))
+
+	assert.NotNil(program.Lookup("unexported", &fail))
+	assert.NotNil(program.Lookup("Add", add))
+	var noError func() int
+	assert.NotNil(program.Lookup("Add", &noError))
+}
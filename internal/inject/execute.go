@@ -0,0 +1,197 @@
+package inject
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+	// inProcessMu serializes the calls to plugin functions, which
+	// change the working directory and environment of gyv's process.
+	inProcessMu sync.Mutex
+
+	// interrupted cancelled when gyv receives SIGINT or SIGTERM.
+	interrupted, interrupt = context.WithCancel(context.Background())
+	running                int32
+)
+
+type execOptionsKey struct{}
+
+// ExecOptions the environment in which injected functions are executed.
+type ExecOptions struct {
+	// Directory the working directory of the injected functions.
+	// Defaults to the project's directory.
+	Directory string
+
+	// Env the environment variables set in addition to gyv's environment.
+	Env map[string]string
+
+	// Timeout cancels the execution after the given duration. No timeout if 0.
+	Timeout time.Duration
+}
+
+// Result the outcome of an operation run with "Execute()".
+type Result struct {
+	// Err the error returned by the operation, a "*PanicError" if it panicked,
+	// or the context's error if it was cancelled or timed out.
+	Err error
+
+	// Duration the time the operation took.
+	Duration time.Duration
+}
+
+// ErrInterrupted returned by "Execute()" when the operation
+// is cancelled because gyv received SIGINT or SIGTERM.
+var ErrInterrupted = errors.New("Interrupted")
+
+// PanicError returned when injected code panics.
+type PanicError struct {
+	Value interface{}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("Injected code panicked: %v", e.Value)
+}
+
+// Execute runs an operation calling injected functions. The injected functions
+// called with the context given to the operation are executed with the given options:
+// with the exec backend, the working directory and environment are the ones of the subprocess;
+// with the plugin backend, they are applied to gyv's process for the duration of the call.
+// The context is cancelled when the timeout is exceeded or when gyv receives SIGINT or SIGTERM.
+// Panics are recovered and reported in the result.
+func Execute(ctx context.Context, options ExecOptions, operation func(ctx context.Context) error) (result *Result) {
+	atomic.AddInt32(&running, 1)
+	defer atomic.AddInt32(&running, -1)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-interrupted.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	if options.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, options.Timeout)
+		defer cancelTimeout()
+	}
+	ctx = context.WithValue(ctx, execOptionsKey{}, options)
+
+	result = &Result{}
+	start := time.Now()
+	defer func() {
+		if reason := recover(); reason != nil {
+			result.Err = &PanicError{reason}
+		}
+		result.Duration = time.Since(start)
+	}()
+	result.Err = operation(ctx)
+	if result.Err != nil && interrupted.Err() != nil && errors.Is(result.Err, context.Canceled) {
+		result.Err = ErrInterrupted
+	}
+	return result
+}
+
+func execOptions(ctx context.Context, directory string) ExecOptions {
+	options, _ := ctx.Value(execOptionsKey{}).(ExecOptions)
+	if options.Directory == "" {
+		options.Directory = directory
+	}
+	return options
+}
+
+// splitContext returns the context passed as first argument of a function
+// looked up with a leading "context.Context" parameter, and the remaining arguments.
+func splitContext(t reflect.Type, args []reflect.Value) (context.Context, []reflect.Value) {
+	if t.NumIn() > 0 && t.In(0) == contextType {
+		if ctx, ok := args[0].Interface().(context.Context); ok && ctx != nil {
+			return ctx, args[1:]
+		}
+		return context.Background(), args[1:]
+	}
+	return context.Background(), args
+}
+
+// withoutContext returns the type of the injected function matching
+// the given lookup target type, which may have a leading "context.Context" parameter.
+func withoutContext(t reflect.Type) reflect.Type {
+	if t.NumIn() == 0 || t.In(0) != contextType {
+		return t
+	}
+	in := make([]reflect.Type, 0, t.NumIn()-1)
+	for i := 1; i < t.NumIn(); i++ {
+		in = append(in, t.In(i))
+	}
+	out := make([]reflect.Type, 0, t.NumOut())
+	for i := 0; i < t.NumOut(); i++ {
+		out = append(out, t.Out(i))
+	}
+	return reflect.FuncOf(in, out, t.IsVariadic())
+}
+
+// callInProcess calls the given plugin function with the working directory and
+// environment variables of the given options. They are restored before the outcome is
+// returned. A plugin function cannot be stopped, so the call always waits for it to return,
+// even if the context is cancelled: returning early would leave the working directory and
+// environment of gyv's process changed while the rest of gyv keeps running.
+// Use the exec backend to cancel injected code.
+func callInProcess(options ExecOptions, fn reflect.Value, args []reflect.Value) (results []reflect.Value, err error) {
+	inProcessMu.Lock()
+	defer inProcessMu.Unlock()
+	restore, err := applyInProcess(options)
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+	defer func() {
+		if reason := recover(); reason != nil {
+			results, err = nil, &PanicError{reason}
+		}
+	}()
+	return fn.Call(args), nil
+}
+
+func applyInProcess(options ExecOptions) (func(), error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chdir(options.Directory); err != nil {
+		return nil, err
+	}
+
+	type previousValue struct {
+		value string
+		isSet bool
+	}
+	previous := make(map[string]previousValue, len(options.Env))
+	restore := func() {
+		for k, v := range previous {
+			if v.isSet {
+				_ = os.Setenv(k, v.value)
+			} else {
+				_ = os.Unsetenv(k)
+			}
+		}
+		_ = os.Chdir(wd)
+	}
+	for k, v := range options.Env {
+		value, isSet := os.LookupEnv(k)
+		previous[k] = previousValue{value, isSet}
+		if err := os.Setenv(k, v); err != nil {
+			restore()
+			return nil, err
+		}
+	}
+	return restore, nil
+}
@@ -0,0 +1,87 @@
+package inject
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecute(t *testing.T) {
+	assert := assert.New(t)
+
+	result := Execute(context.Background(), ExecOptions{}, func(ctx context.Context) error {
+		return nil
+	})
+	assert.Nil(result.Err)
+
+	err := errors.New("test error")
+	result = Execute(context.Background(), ExecOptions{}, func(ctx context.Context) error {
+		return err
+	})
+	assert.Equal(err, result.Err)
+
+	result = Execute(context.Background(), ExecOptions{}, func(ctx context.Context) error {
+		panic("test panic")
+	})
+	assert.Equal(&PanicError{"test panic"}, result.Err)
+	assert.EqualError(result.Err, "Injected code panicked: test panic")
+
+	result = Execute(context.Background(), ExecOptions{Timeout: 10 * time.Millisecond}, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.Equal(context.DeadlineExceeded, result.Err)
+	assert.GreaterOrEqual(int64(result.Duration), int64(10*time.Millisecond))
+
+	options := ExecOptions{Directory: "project", Env: map[string]string{"KEY": "value"}}
+	result = Execute(context.Background(), options, func(ctx context.Context) error {
+		assert.Equal(options, execOptions(ctx, "default"))
+		return nil
+	})
+	assert.Nil(result.Err)
+	assert.Equal(ExecOptions{Directory: "default"}, execOptions(context.Background(), "default"))
+}
+
+func TestCallInProcess(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := ExecOptions{Directory: dir, Env: map[string]string{"GYV_TEST_IN_PROCESS": "value"}}
+
+	fn := func(prefix string) (string, string, error) {
+		wd, err := os.Getwd()
+		return prefix + wd, os.Getenv("GYV_TEST_IN_PROCESS"), err
+	}
+	results, err := callInProcess(options, reflect.ValueOf(fn), []reflect.Value{reflect.ValueOf("wd: ")})
+	assert.Nil(err)
+	assert.Equal("wd: "+dir, results[0].Interface())
+	assert.Equal("value", results[1].Interface())
+
+	// The working directory and environment are restored
+	current, err := os.Getwd()
+	assert.Nil(err)
+	assert.Equal(wd, current)
+	_, isSet := os.LookupEnv("GYV_TEST_IN_PROCESS")
+	assert.False(isSet)
+
+	panicking := func() error {
+		panic("test panic")
+	}
+	_, err = callInProcess(options, reflect.ValueOf(panicking), []reflect.Value{})
+	assert.Equal(&PanicError{"test panic"}, err)
+	current, err = os.Getwd()
+	assert.Nil(err)
+	assert.Equal(wd, current)
+}
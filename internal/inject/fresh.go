@@ -0,0 +1,46 @@
+package inject
+
+import (
+	"context"
+
+	"goyave.dev/gyv/internal/stub"
+)
+
+// Fresh generate and return database reset function.
+// The returned function drops the tables of all registered models,
+// runs the migrations, then runs the given seeders. The names of the
+// applied versioned migrations are returned.
+// Everything is done in a single injection so the project is only built once.
+func Fresh(directory string, layout DatabaseLayout, seeders []*SeederFunction) (func(ctx context.Context) ([]string, error), error) {
+	injector, err := NewInjector(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	migrateData, err := migrateStubData(injector, layout)
+	if err != nil {
+		return nil, err
+	}
+	seederData, err := seederStubData(injector, layout, seeders)
+	if err != nil {
+		return nil, err
+	}
+
+	injector.StubName = stub.InjectFresh
+	injector.StubData = stub.Data{}
+	for _, data := range []stub.Data{migrateData, seederData} {
+		for k, v := range data {
+			injector.StubData[k] = v
+		}
+	}
+
+	program, err := injector.Inject()
+	if err != nil {
+		return nil, err
+	}
+	var fresh func(context.Context) ([]string, error)
+	if err := program.Lookup("Fresh", &fresh); err != nil {
+		return nil, err
+	}
+	return fresh, nil
+}
@@ -0,0 +1,88 @@
+package inject
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/gyv/internal/stub"
+)
+
+func TestFreshStub(t *testing.T) {
+	cases := []struct {
+		desc       string
+		migrations bool
+		seeders    []string
+		calls      []string
+	}{
+		{
+			desc:  "auto-migrations only",
+			calls: []string{"DropTable(", "database.Migrate()"},
+		},
+		{
+			desc:       "versioned migrations",
+			migrations: true,
+			calls:      []string{"migration.Rollback(db, math.MaxInt32)", "DropTable(", "migration.Reset(db)", "database.Migrate()", "migration.Migrate(db)"},
+		},
+		{
+			desc:       "seeders",
+			migrations: true,
+			seeders:    []string{"seeder/billing.Invoices", "seeder.Run"},
+			calls:      []string{"migration.Rollback(db, math.MaxInt32)", "DropTable(", "migration.Reset(db)", "database.Migrate()", "migration.Migrate(db)", "seeder0.Invoices()", "seeder1.Run()"},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			injector := testDatabaseInjector(t, c.migrations)
+
+			functions, err := FindSeeders(injector.directory, "example", DefaultDatabaseLayout.SeederRoot)
+			if !assert.Nil(err) {
+				return
+			}
+			seeders := []*SeederFunction{}
+			for _, name := range c.seeders {
+				for _, f := range functions {
+					if f.String() == name {
+						seeders = append(seeders, f)
+					}
+				}
+			}
+			if !assert.Len(seeders, len(c.seeders)) {
+				return
+			}
+
+			data := stub.Data{}
+			migrateData, err := migrateStubData(injector, DefaultDatabaseLayout)
+			if !assert.Nil(err) {
+				return
+			}
+			seederData, err := seederStubData(injector, DefaultDatabaseLayout, seeders)
+			if !assert.Nil(err) {
+				return
+			}
+			for _, d := range []stub.Data{migrateData, seederData} {
+				for k, v := range d {
+					data[k] = v
+				}
+			}
+
+			_, source := loadTestStub(t, stub.InjectFresh, data)
+
+			// The versioned migrations are rolled back before the
+			// tables are dropped, and the seeders run last.
+			index := 0
+			for _, call := range c.calls {
+				i := strings.Index(source[index:], call)
+				if !assert.NotEqual(-1, i, call) {
+					return
+				}
+				index += i + len(call)
+			}
+			assert.Equal(c.migrations, strings.Contains(source, "migration."))
+			assert.Equal(c.migrations, strings.Contains(source, "could not roll back the versioned migrations"))
+		})
+	}
+}
@@ -1,19 +1,24 @@
 package inject
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"plugin"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Masterminds/semver"
 	"golang.org/x/mod/modfile"
+	"goyave.dev/gyv/internal/config"
 	"goyave.dev/gyv/internal/mod"
 	"goyave.dev/gyv/internal/stub"
 )
@@ -21,6 +26,10 @@ import (
 var (
 	minimumGoyaveVersion = semver.MustParse("v3.9.1")
 
+	// DefaultBuildOptions the build options overriding the build
+	// configuration of the project for all new injectors.
+	DefaultBuildOptions config.Build
+
 	// ErrUnsupportedGoyaveVersion returned when using NewInjector
 	// with a Goyave project using an outdated version of the framework
 	ErrUnsupportedGoyaveVersion = fmt.Errorf("Unsupported Goyave version. Minimum version: %s", minimumGoyaveVersion.Original())
@@ -29,8 +38,15 @@ var (
 // FunctionCall is a string representation of a function call or reference
 // with its matching import. Doesn't support functions with parameters.
 type FunctionCall struct {
+	// Package the import of the package declaring the function.
+	// Nil if the function is declared in the main package.
 	Package *ast.ImportSpec
-	Value   string
+
+	// Qualifier the name referencing the package in "Value" (e.g.: "route").
+	// Empty if the function is declared in the main package.
+	Qualifier string
+
+	Value string
 }
 
 // Dependency a library that needs to be imported for the planned injection.
@@ -42,8 +58,9 @@ type Dependency struct {
 	Version string
 }
 
-// Injector code injector for Goyave projects. Builds a temporary source file at
-// the project's root, build the project in plugin mode and return a Plugin instance.
+// Injector code injector for Goyave projects. Builds a temporary source file
+// virtually placed in the project's main package (the project's files are not modified), build the project in plugin mode (or as an executable with
+// the exec backend) and return a Program giving access to the injected functions.
 type Injector struct {
 	directory        string
 	ModFile          *modfile.File
@@ -56,10 +73,44 @@ type Injector struct {
 	// StubData the data to inject into the stub.
 	StubData stub.Data
 
+	// PackageDirectory the slash-separated path to the main package the
+	// injected code is placed in, relative to the project root.
+	// Defaults to the first main package of the project, or the project
+	// root if the project doesn't have any main package.
+	PackageDirectory string
+
+	// MigrationRoot the slash-separated path to the directory containing the
+	// versioned migrations, relative to the project root.
+	// Defaults to the project's configuration.
+	MigrationRoot string
+
+	// Inputs the values detected in the project and used to generate the
+	// injected code. They are used to explain compilation errors.
+	Inputs []*Input
+
 	// Dependencies list of libraries that need to be imported
 	// for the planned injection. These libraries will be added
-	// automatically using "go get" and removed after the build is complete.
+	// automatically using "go get" to a temporary copy of "go.mod".
 	Dependencies []Dependency
+
+	// Stdout the writer receiving the progress messages and the output
+	// of the "go" commands. Defaults to "os.Stdout".
+	Stdout io.Writer
+
+	// BuildOptions the options applied to every "go" command run by the injector.
+	// Defaults to the project's build configuration, overridden by "DefaultBuildOptions".
+	BuildOptions config.Build
+
+	// Backend the way the injected code is built and executed.
+	// Defaults to "DefaultBackend".
+	Backend Backend
+
+	// Cache the cache storing the compiled plugins and executables.
+	// If nil, the injected code is built every time and deleted once
+	// it is not needed anymore.
+	// Defaults to the cache located in the user cache directory, unless
+	// "NoCache" is true.
+	Cache *Cache
 }
 
 // NewInjector create a new injector for the project in the given directory.
@@ -68,6 +119,8 @@ type Injector struct {
 func NewInjector(directory string) (*Injector, error) {
 	injector := &Injector{
 		directory: directory,
+		Stdout:    os.Stdout,
+		Backend:   DefaultBackend,
 	}
 	modFile, err := mod.Parse(directory)
 	if err != nil {
@@ -86,63 +139,203 @@ func NewInjector(directory string) (*Injector, error) {
 		return nil, ErrUnsupportedGoyaveVersion
 	}
 
+	project, err := config.LoadProject(directory)
+	if err != nil {
+		return nil, err
+	}
+	injector.BuildOptions = project.Build.Merge(DefaultBuildOptions)
+	injector.MigrationRoot = project.Database.MigrationDirectory()
+
+	if mainPackage, err := findMainPackage(directory); err == nil {
+		injector.PackageDirectory = mainPackage
+	}
+
+	if !NoCache {
+		if cache, err := NewCache(); err == nil {
+			injector.Cache = cache
+		}
+	}
+
 	injector.GoyaveVersion = goyaveVersion
 	injector.GoyaveImportPath = goyaveMod.Mod.Path
 	injector.ModFile = modFile
 	return injector, nil
 }
 
-// Inject writes temporary source file, compiles plugin, loads it and
-// cleans temporary source file and compiled plugin.
-func (i *Injector) Inject() (*plugin.Plugin, error) {
-	pluginPath := generatePluginPath()
-	if err := i.build(pluginPath); err != nil {
+// Inject writes temporary source file in a temporary workspace, compiles it
+// using the injector's backend, loads it and cleans the workspace.
+// If the injector has a cache and a plugin or executable built from the same
+// inputs is found in it, it is loaded without building anything.
+// Otherwise the compiled plugin or executable is stored in the cache, or deleted
+// if the injector doesn't have a cache.
+func (i *Injector) Inject() (Program, error) {
+	backend := i.Backend
+	if backend == "" {
+		backend = BackendAuto
+	}
+	if err := backend.Validate(); err != nil {
 		return nil, err
 	}
-	defer func() {
-		if err := os.Remove(pluginPath); err != nil {
-			fmt.Println("⚠️ WARNING: could not delete compiled plugin at", pluginPath)
-		}
-	}()
-	return plugin.Open(pluginPath)
+
+	source, err := i.renderStub()
+	if err != nil {
+		return nil, err
+	}
+
+	if backend != BackendAuto {
+		return i.inject(backend, source)
+	}
+	if !i.pluginSupported() {
+		return i.inject(BackendExec, source)
+	}
+	program, err := i.inject(BackendPlugin, source)
+	if lErr := (*loadError)(nil); errors.As(err, &lErr) {
+		fmt.Fprintln(i.Stdout, "⚠️ Could not load plugin, falling back to exec backend:", lErr)
+		return i.inject(BackendExec, source)
+	}
+	return program, err
 }
 
-func (i *Injector) build(output string) error {
-	fmt.Println("⚙️ Building plugin")
-	fileName := generateTempFileName(i.directory)
-	if err := i.writeTemporaryFile(fileName); err != nil {
-		return err
+func (i *Injector) inject(backend Backend, source []byte) (Program, error) {
+	if i.Cache == nil {
+		output := generateOutputPath(backend)
+		if err := i.build(output, backend, source); err != nil {
+			return nil, err
+		}
+		if backend == BackendExec {
+			// The executable is run each time an injected function is called
+			registerTemporaryFile(output)
+			return i.open(output, backend, source)
+		}
+		defer func() {
+			if err := os.Remove(output); err != nil {
+				fmt.Fprintln(i.Stdout, "⚠️ WARNING: could not delete compiled plugin at", output)
+			}
+		}()
+		return i.open(output, backend, source)
 	}
 
-	dependencies := i.getDependencies()
+	key, err := i.cacheKey(backend, source)
+	if err != nil {
+		return nil, err
+	}
+	if output, ok := i.Cache.Lookup(key, backend.extension()); ok {
+		fmt.Fprintln(i.Stdout, "♻️ Using cached", backend)
+		return i.open(output, backend, source)
+	}
 
+	if err := os.MkdirAll(i.Cache.Directory, 0755); err != nil {
+		return nil, err
+	}
+	output := i.Cache.Path(key, backend.extension())
+	tmpPath := fmt.Sprintf("%s.%d.tmp", output, os.Getpid())
+	if err := i.build(tmpPath, backend, source); err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, err
+	}
+	if err := os.Rename(tmpPath, output); err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, err
+	}
+	return i.open(output, backend, source)
+}
+
+func (i *Injector) open(path string, backend Backend, source []byte) (Program, error) {
+	if backend == BackendPlugin {
+		return openPlugin(path, i.directory)
+	}
+	functions, err := findExportedFunctionsInSource(source)
+	if err != nil {
+		return nil, err
+	}
+	return &execProgram{
+		path:      path,
+		directory: i.directory,
+		functions: functions,
+		stderr:    os.Stderr,
+	}, nil
+}
+
+func (i *Injector) build(output string, backend Backend, source []byte) error {
+	fmt.Fprintln(i.Stdout, "⚙️ Building", backend)
+	workspace, err := newWorkspace(i.directory)
+	if err != nil {
+		return err
+	}
 	defer func() {
-		fmt.Println("🧹 Cleanup")
-		if err := os.Remove(fileName); err != nil {
-			fmt.Println("⚠️ WARNING: could not delete temporary code injection file", fileName)
+		fmt.Fprintln(i.Stdout, "🧹 Cleanup")
+		if workspace.kept {
 			return
 		}
-		// Remove go.sum unused entries
-		if err := i.executeCommand("go", "mod", "tidy"); err != nil {
-			fmt.Println("⚠️ WARNING: \"go mod tidy\" failed")
+		if err := workspace.remove(); err != nil {
+			fmt.Fprintln(i.Stdout, "⚠️ WARNING: could not delete temporary code injection directory", workspace.directory)
 		}
 	}()
 
-	for _, d := range dependencies {
+	fileName := generateTempFileName()
+	packageDirectory := filepath.FromSlash(i.PackageDirectory)
+	if err := workspace.addFile(filepath.Join(packageDirectory, fileName), source); err != nil {
+		return err
+	}
+	generated := map[string][]byte{fileName: source}
+
+	args := append([]string{"build"}, i.buildFlags()...)
+	args = append(args, "-ldflags", "-w -s", "-o", output)
+	if backend == BackendExec {
+		execMain, err := renderExecMain(source)
+		if err != nil {
+			return err
+		}
+		execFileName := strings.TrimSuffix(fileName, ".go") + "_exec.go"
+		if err := workspace.addFile(filepath.Join(packageDirectory, execFileName), execMain); err != nil {
+			return err
+		}
+		generated[execFileName] = execMain
+	} else {
+		args = append(args, "-buildmode=plugin")
+	}
+
+	for _, d := range i.getDependencies() {
+		if i.BuildOptions.Vendor {
+			return fmt.Errorf("%q cannot be added in vendor mode: add it to the project's dependencies and vendor it first", d.Name)
+		}
 		dep := d.Name
 		if d.Version != "" {
 			dep += "@" + d.Version
 		}
-		if err := i.executeCommand("go", "get", dep); err != nil {
+		args := append([]string{"get"}, i.buildFlags()...)
+		if err := i.executeCommand(append(args, workspace.modFileFlag(), dep)...); err != nil {
 			return err
 		}
 	}
 
-	cmd := exec.Command("go", "build", "-ldflags", "-w -s", "-buildmode=plugin", "-o", output)
-	cmd.Dir = i.directory
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	overlayFlag, err := workspace.overlayFlag()
+	if err != nil {
+		return err
+	}
+	args = append(args, workspace.modFileFlag(), overlayFlag, i.packagePattern())
+
+	compilerOutput := &bytes.Buffer{}
+	cmd := i.goCommand(args...)
+	cmd.Stdout = i.Stdout
+	cmd.Stderr = compilerOutput
+	if err := cmd.Run(); err != nil {
+		buildErr := i.newBuildError(err, compilerOutput.String(), generated)
+		if KeepTemp {
+			workspace.keep()
+			buildErr.KeptDirectory = workspace.directory
+		}
+		return buildErr
+	}
+	return nil
+}
+
+// packagePattern returns the pattern of the package to build.
+func (i *Injector) packagePattern() string {
+	if i.PackageDirectory == "" {
+		return "."
+	}
+	return "./" + strings.Trim(i.PackageDirectory, "/")
 }
 
 func (i *Injector) getDependencies() []Dependency {
@@ -155,30 +348,64 @@ func (i *Injector) getDependencies() []Dependency {
 	return dependencies
 }
 
-func (i *Injector) executeCommand(name string, args ...string) error {
-	cmd := exec.Command(name, args...)
+// goCommand returns a "go" command with the given arguments, using
+// the go binary, GOFLAGS and GOTOOLCHAIN of the injector's build options.
+func (i *Injector) goCommand(args ...string) *exec.Cmd {
+	binary := i.BuildOptions.Go
+	if binary == "" {
+		binary = "go"
+	}
+	cmd := exec.Command(binary, args...)
 	cmd.Dir = i.directory
-	cmd.Stdout = os.Stdout
+	cmd.Env = append(os.Environ(), "GOWORK=off") // "-modfile" is not supported in workspace mode
+	if i.BuildOptions.GoFlags != "" {
+		cmd.Env = append(cmd.Env, "GOFLAGS="+strings.TrimSpace(os.Getenv("GOFLAGS")+" "+i.BuildOptions.GoFlags))
+	}
+	if i.BuildOptions.Toolchain != "" {
+		cmd.Env = append(cmd.Env, "GOTOOLCHAIN="+i.BuildOptions.Toolchain)
+	}
+	return cmd
+}
+
+// buildFlags returns the build flags matching the injector's build options.
+func (i *Injector) buildFlags() []string {
+	flags := []string{}
+	if len(i.BuildOptions.Tags) > 0 {
+		flags = append(flags, "-tags="+strings.Join(i.BuildOptions.Tags, ","))
+	}
+	if i.BuildOptions.Vendor {
+		flags = append(flags, "-mod=vendor")
+	}
+	return flags
+}
+
+func (i *Injector) executeCommand(args ...string) error {
+	cmd := i.goCommand(args...)
+	cmd.Stdout = i.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
-func (i *Injector) writeTemporaryFile(dest string) error {
+func (i *Injector) renderStub() ([]byte, error) {
 	data := stub.Data{"GoyaveImportPath": i.GoyaveImportPath}
 	for k, v := range i.StubData {
 		data[k] = v
 	}
 	s, err := stub.Load(i.StubName, data)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return s.Bytes(), nil
+}
+
+func writeTemporaryFile(dest string, source []byte) error {
 	file, err := os.OpenFile(dest, os.O_RDWR|os.O_CREATE, 0644)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = file.Close() }()
 
-	if _, err := file.WriteString(s.String()); err != nil {
+	if _, err := file.Write(source); err != nil {
 		_ = os.Remove(dest)
 		return err
 	}
@@ -267,120 +494,294 @@ func GetBlankImports(directory string) ([]string, error) {
 	return imports, nil
 }
 
-// FindRouteRegistrer tries to find the route registrer function from Go files
-// inside the given directory using the Go AST. Sub-directories are not checked.
-// If `goyave.Start()` is found, then the parameter passed to it is assumed to be
-// the main route registrer function. Import aliases are supported. To properly identify
-// `goyave.Start()`, this function needs the Goyave import path specified in `go.mod`.
-func FindRouteRegistrer(directory string, goyaveImportPath string) (*FunctionCall, error) {
-	var routeRegister *FunctionCall
-	files, err := findGoFiles(directory)
+// FindRouteRegistrer tries to find the route registrer function from the main packages
+// of the module in the given directory using the Go AST. The project root is checked first.
+// For Goyave v3 and v4, if `goyave.Start()` is found, then the parameter passed to it
+// is assumed to be the main route registrer function. For Goyave v5, the parameter
+// passed to `server.RegisterRoutes()` is used instead. Import aliases are supported.
+// To properly identify these calls, this function needs the Goyave import path specified in `go.mod`.
+//
+// The route registrer can be:
+//   - a function declared in the main package: `goyave.Start(registerRoutes)`
+//   - an exported function of an imported package: `goyave.Start(route.Register)`
+//   - a method value on a package-level variable: `goyave.Start(app.RegisterRoutes)`
+//   - a call without arguments to any of the above returning the registrer: `goyave.Start(route.NewRegistrer())`
+//
+// An error explaining why is returned for any other expression. An error is also returned
+// if different route registrers are found in several main packages.
+func FindRouteRegistrer(directory string, goyaveImportPath string) (*RouteRegistrer, error) {
+	packages, err := FindPackages(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	takesServer := goyaveMajorVersion(goyaveImportPath) >= 5
+	found := []*RouteRegistrer{}
+	var firstErr error
+	for _, pkg := range packages {
+		call, err := findRouteRegistrerInPackage(directory, pkg, goyaveImportPath, takesServer)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if call == nil {
+			continue
+		}
+		registrer := &RouteRegistrer{FunctionCall: call, Directory: pkg, TakesServer: takesServer}
+		if pkg == "." {
+			registrer.Directory = ""
+		}
+		found = append(found, registrer)
+	}
+
+	switch {
+	case len(found) > 1:
+		candidates := make([]string, 0, len(found))
+		for _, r := range found {
+			candidates = append(candidates, r.String())
+		}
+		return nil, fmt.Errorf("%w: %s. Use --registrar to select one", ErrAmbiguousRouteRegistrer, strings.Join(candidates, ", "))
+	case len(found) == 1:
+		return found[0], nil
+	case firstErr != nil:
+		return nil, firstErr
+	}
+	if takesServer {
+		return nil, fmt.Errorf("Could not find any valid call of \"server.RegisterRoutes()\" in the main packages. Use --registrar to specify the route registrer")
+	}
+	return nil, fmt.Errorf("Could not find any valid call of \"goyave.Start()\" in the main packages. Use --registrar to specify the route registrer")
+}
+
+// findRouteRegistrerInPackage returns the route registrer found in the given package
+// (relative to the project directory), or nil if it isn't a main package or doesn't register routes.
+func findRouteRegistrerInPackage(directory, pkg string, goyaveImportPath string, takesServer bool) (*FunctionCall, error) {
+	files, err := findGoFiles(filepath.Join(directory, filepath.FromSlash(pkg)))
 	if err != nil {
 		return nil, err
 	}
 
+	fset := token.NewFileSet()
+	astFiles := make([]*ast.File, 0, len(files))
 	for _, f := range files {
+		if strings.HasSuffix(f, "_test.go") {
+			continue
+		}
 		src, err := os.ReadFile(f)
 		if err != nil {
 			return nil, err
 		}
 
-		fset := token.NewFileSet()
-
-		astFile, err := parser.ParseFile(fset, filepath.Base(f), src, parser.ParseComments)
+		astFile, err := parser.ParseFile(fset, filepath.Join(filepath.FromSlash(pkg), filepath.Base(f)), src, parser.ParseComments)
 		if err != nil {
 			return nil, err
 		}
+		if astFile.Name.Name != "main" {
+			return nil, nil
+		}
+		astFiles = append(astFiles, astFile)
+	}
+	declared := findPackageLevelDeclarations(astFiles)
+
+	var routeRegister *FunctionCall
+	var routeRegisterErr error
+	for _, astFile := range astFiles {
+		goyaveImportName := "goyave"
+		if n := findImportAlias(astFile.Imports, goyaveImportPath); n != "" {
+			goyaveImportName = n
+		}
+		if !importsPackage(astFile.Imports, goyaveImportPath) {
+			continue
+		}
 
 		ast.Inspect(astFile, func(n ast.Node) bool {
-			if routeRegister != nil {
+			if routeRegister != nil || routeRegisterErr != nil {
 				return false
 			}
 
-			goyaveImportName := "goyave"
-			if n := findImportAlias(astFile.Imports, goyaveImportPath); n != "" {
-				goyaveImportName = n
-			}
-
 			call, ok := n.(*ast.CallExpr)
-			if ok {
-				if fn, ok := call.Fun.(*ast.SelectorExpr); ok {
-					selector, okSelector := fn.X.(*ast.Ident)
-					if !okSelector || selector.Name != goyaveImportName || len(call.Args) == 0 || fn.Sel.Name != "Start" {
-						return false
-					}
-					routeRegister = argToFunctionCall(astFile, call.Args[0])
-					return false
+			if !ok || len(call.Args) != 1 {
+				return true
+			}
+			fn, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if takesServer {
+				// server.RegisterRoutes(route.Register)
+				if fn.Sel.Name != "RegisterRoutes" {
+					return true
+				}
+			} else {
+				// goyave.Start(route.Register)
+				selector, okSelector := fn.X.(*ast.Ident)
+				if !okSelector || selector.Name != goyaveImportName || fn.Sel.Name != "Start" {
+					return true
 				}
 			}
-			return true
+			routeRegister, err = argToFunctionCall(astFile, declared, call.Args[0])
+			if err != nil {
+				routeRegisterErr = fmt.Errorf("Unsupported route registrer \"%s\" passed to \"%s()\" in %s: %w", types.ExprString(call.Args[0]), types.ExprString(fn), fset.Position(call.Args[0].Pos()), err)
+			}
+			return false
 		})
 	}
 
-	if routeRegister == nil {
-		return nil, fmt.Errorf("Could not find any valid call of \"goyave.Start()\"")
+	return routeRegister, routeRegisterErr
+}
+
+// routeRegistrerAlias the alias of the route registrer's package in injected code,
+// preventing conflicts with the other imports of the stubs.
+const routeRegistrerAlias = "gyvroutes"
+
+// routeRegistrerStubData returns the stub data used to import and call the given
+// route registrer: "RouteRegistrerImportPath" and "RouteRegistrer".
+func routeRegistrerStubData(call *RouteRegistrer) stub.Data {
+	importPath := ""
+	value := call.Value
+	if call.Package != nil {
+		importPath = routeRegistrerAlias + " " + call.Package.Path.Value
+		value = routeRegistrerAlias + strings.TrimPrefix(value, call.Qualifier)
+	}
+	return stub.Data{
+		"RouteRegistrerImportPath": importPath,
+		"RouteRegistrer":           value,
+	}
+}
+
+func routeRegistrerInput(call *RouteRegistrer) *Input {
+	hint := "The route registrar is the argument of \"goyave.Start()\" in the main package: make sure it is a function taking a \"*goyave.Router\" and that its package can be imported."
+	if call.TakesServer {
+		hint = "The route registrar is the argument of \"server.RegisterRoutes()\" in the main package: make sure it is a function taking a \"*goyave.Server\" and a \"*goyave.Router\" and that its package can be imported."
+	}
+	return &Input{
+		Name:   "route registrar",
+		Value:  routeRegistrerStubData(call)["RouteRegistrer"].(string),
+		Origin: ImportToString(call.Package),
+		Hint:   hint,
 	}
-	return routeRegister, nil
 }
 
 func findImportAlias(imports []*ast.ImportSpec, importPath string) string {
 	for _, i := range imports {
-		if i.Name != nil && i.Path.Value == importPath {
+		if path, err := strconv.Unquote(i.Path.Value); err == nil && i.Name != nil && path == importPath {
 			return i.Name.Name
 		}
 	}
 	return ""
 }
 
-func argToFunctionCall(astFile *ast.File, argExpr ast.Expr) *FunctionCall {
+// findPackageLevelDeclarations returns the names of the functions (excluding methods)
+// and variables declared at package level in the given files.
+func findPackageLevelDeclarations(astFiles []*ast.File) map[string]bool {
+	declared := map[string]bool{}
+	for _, astFile := range astFiles {
+		for _, decl := range astFile.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil {
+					declared[d.Name.Name] = true
+				}
+			case *ast.GenDecl:
+				if d.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range d.Specs {
+					for _, name := range spec.(*ast.ValueSpec).Names {
+						declared[name.Name] = true
+					}
+				}
+			}
+		}
+	}
+	return declared
+}
+
+func argToFunctionCall(astFile *ast.File, declared map[string]bool, argExpr ast.Expr) (*FunctionCall, error) {
 	switch arg := argExpr.(type) {
 	case *ast.Ident:
+		if !declared[arg.Name] {
+			return nil, fmt.Errorf("%q is not a function or variable declared at package level in the main package", arg.Name)
+		}
 		return &FunctionCall{
 			Value: arg.Name,
-		}
+		}, nil
 	case *ast.SelectorExpr:
-		if selector, ok := arg.X.(*ast.Ident); ok {
-			selectorName := selector.Name
-
+		selector, ok := arg.X.(*ast.Ident)
+		if !ok {
+			return nil, fmt.Errorf("method values on expressions are not supported, assign the registrer to a package-level variable or wrap it in a function")
+		}
+		if pkg := findImport(astFile.Imports, selector.Name); pkg != nil {
 			return &FunctionCall{
-				Value:   fmt.Sprintf("%s.%s", selectorName, arg.Sel.Name),
-				Package: findImport(astFile.Imports, selectorName),
-			}
+				Value:     fmt.Sprintf("%s.%s", selector.Name, arg.Sel.Name),
+				Qualifier: selector.Name,
+				Package:   pkg,
+			}, nil
+		}
+		if declared[selector.Name] {
+			// Method value on a package-level variable of the main package
+			return &FunctionCall{
+				Value: fmt.Sprintf("%s.%s", selector.Name, arg.Sel.Name),
+			}, nil
 		}
+		return nil, fmt.Errorf("%q is neither an imported package nor a variable declared at package level in the main package", selector.Name)
 	case *ast.CallExpr:
-		if len(arg.Args) == 0 {
+		if len(arg.Args) != 0 {
 			// If the call has arguments, then we cannot ensure copying these arguments will work
-			switch fun := arg.Fun.(type) {
-			case *ast.Ident:
-				return &FunctionCall{
-					Value: fmt.Sprintf("%s()", fun.Name),
-				}
-			case *ast.SelectorExpr:
-				if selector, ok := fun.X.(*ast.Ident); ok {
-					selectorName := selector.Name
-					return &FunctionCall{
-						Value:   fmt.Sprintf("%s.%s()", selectorName, fun.Sel.Name),
-						Package: findImport(astFile.Imports, selectorName),
-					}
-				}
-			}
+			return nil, fmt.Errorf("calls with arguments are not supported, wrap the call in a function without parameters")
+		}
+		if _, ok := arg.Fun.(*ast.CallExpr); ok {
+			return nil, fmt.Errorf("chained calls are not supported, wrap the call in a function without parameters")
 		}
+		call, err := argToFunctionCall(astFile, declared, arg.Fun)
+		if err != nil {
+			return nil, err
+		}
+		call.Value += "()"
+		return call, nil
+	case *ast.FuncLit:
+		return nil, fmt.Errorf("function literals are not supported, declare the registrer as a function")
 	}
-	return nil
+	return nil, fmt.Errorf("expressions of type %T are not supported", argExpr)
 }
 
+// findImport returns the import matching the given package name: either the import
+// alias or the last element of the import path, ignoring major version suffixes.
 func findImport(imports []*ast.ImportSpec, name string) *ast.ImportSpec {
 	for _, i := range imports {
-		path := i.Path.Value[1 : len(i.Path.Value)-1]
-		if (i.Name != nil && i.Name.Name == name) || strings.HasSuffix(path, name) {
-			// FIXME Suffix method doesn't always work (for example if import path differs from actual package name)
-			// This solution is acceptable because this case is supposed to be rare.
+		path, err := strconv.Unquote(i.Path.Value)
+		if err != nil {
+			continue
+		}
+		if i.Name != nil {
+			if i.Name.Name == name {
+				return i
+			}
+			continue
+		}
+		// FIXME The package name can still differ from the last element of the import path.
+		// This solution is acceptable because this case is supposed to be rare.
+		elements := strings.Split(path, "/")
+		last := elements[len(elements)-1]
+		if len(elements) > 1 && isMajorVersion(last) {
+			last = elements[len(elements)-2]
+		}
+		if last == name || strings.TrimPrefix(last, "go-") == name {
 			return i
 		}
 	}
 	return nil
 }
 
+func isMajorVersion(element string) bool {
+	if len(element) < 2 || element[0] != 'v' {
+		return false
+	}
+	_, err := strconv.Atoi(element[1:])
+	return err == nil
+}
+
 func findGoFiles(directory string) ([]string, error) {
 	files := []string{}
 	err := filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
@@ -395,10 +796,10 @@ func findGoFiles(directory string) ([]string, error) {
 	return files, err
 }
 
-func generateTempFileName(parent string) string {
-	return fmt.Sprintf("%s%ccodeinject-%d.go", parent, os.PathSeparator, time.Now().Unix())
+func generateTempFileName() string {
+	return fmt.Sprintf("codeinject-%d.go", time.Now().Unix())
 }
 
-func generatePluginPath() string {
-	return fmt.Sprintf("%s%cgyv-code-injection-%d.go", os.TempDir(), os.PathSeparator, time.Now().Unix())
+func generateOutputPath(backend Backend) string {
+	return fmt.Sprintf("%s%cgyv-code-injection-%d%s", os.TempDir(), os.PathSeparator, time.Now().Unix(), backend.extension())
 }
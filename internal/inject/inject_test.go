@@ -0,0 +1,258 @@
+package inject
+
+import (
+	"errors"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"goyave.dev/gyv/internal/config"
+	"goyave.dev/gyv/internal/stub"
+)
+
+func TestGoCommand(t *testing.T) {
+	assert := assert.New(t)
+	previous, isSet := os.LookupEnv("GOFLAGS")
+	if err := os.Setenv("GOFLAGS", "-trimpath"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if isSet {
+			_ = os.Setenv("GOFLAGS", previous)
+		} else {
+			_ = os.Unsetenv("GOFLAGS")
+		}
+	}()
+	injector := &Injector{directory: "project"}
+
+	cmd := injector.goCommand("build")
+	assert.Equal([]string{"go", "build"}, cmd.Args)
+	assert.Equal("project", cmd.Dir)
+	assert.Contains(cmd.Env, "GOWORK=off")
+	assert.Empty(injector.buildFlags())
+
+	injector.BuildOptions = config.Build{
+		Tags:      []string{"sqlite", "json1"},
+		GoFlags:   "-buildvcs=false",
+		Go:        "/usr/local/go/bin/go",
+		Toolchain: "go1.21.5",
+		Vendor:    true,
+	}
+	cmd = injector.goCommand("version")
+	assert.Equal([]string{"/usr/local/go/bin/go", "version"}, cmd.Args)
+	assert.Equal("GOFLAGS=-trimpath -buildvcs=false", cmd.Env[len(cmd.Env)-2])
+	assert.Equal("GOTOOLCHAIN=go1.21.5", cmd.Env[len(cmd.Env)-1])
+	assert.Equal([]string{"-tags=sqlite,json1", "-mod=vendor"}, injector.buildFlags())
+}
+
+func TestNewInjectorPackageDirectory(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example\n\nrequire goyave.dev/goyave/v4 v4.0.0\n")
+	writeTestFile(t, filepath.Join(dir, "app.go"), "package app\n")
+
+	injector, err := NewInjector(dir)
+	if !assert.Nil(err) {
+		return
+	}
+	assert.Empty(injector.PackageDirectory) // No main package
+
+	writeTestFile(t, filepath.Join(dir, "cmd", "server", "main.go"), "package main\n\nfunc main() {}\n")
+	injector, err = NewInjector(dir)
+	if !assert.Nil(err) {
+		return
+	}
+	assert.Equal("cmd/server", injector.PackageDirectory)
+}
+
+func TestFindRouteRegistrer(t *testing.T) {
+	cases := []struct {
+		desc    string
+		source  string
+		value   string
+		stub    string
+		imports string
+		err     string
+	}{
+		{
+			desc:    "imported function",
+			source:  "import (\n\t\"goyave.dev/goyave/v4\"\n\t\"example/http/route\"\n)\n\nfunc main() {\n\tgoyave.Start(route.Register)\n}\n",
+			value:   "route.Register",
+			stub:    "gyvroutes.Register",
+			imports: `gyvroutes "example/http/route"`,
+		},
+		{
+			desc:    "aliased imports",
+			source:  "import (\n\tg \"goyave.dev/goyave/v4\"\n\troutes \"example/http/route\"\n)\n\nfunc main() {\n\tif err := g.Start(routes.Register); err != nil {\n\t\tpanic(err)\n\t}\n}\n",
+			value:   "routes.Register",
+			stub:    "gyvroutes.Register",
+			imports: `gyvroutes "example/http/route"`,
+		},
+		{
+			desc:   "function in main",
+			source: "import \"goyave.dev/goyave/v4\"\n\nfunc main() {\n\tlog.Fatal(goyave.Start(registerRoutes))\n}\n\nfunc registerRoutes(router *goyave.Router) {}\n",
+			value:  "registerRoutes",
+			stub:   "registerRoutes",
+		},
+		{
+			desc:   "method value on package-level variable",
+			source: "import \"goyave.dev/goyave/v4\"\n\nvar app = &App{}\n\nfunc main() {\n\tgoyave.Start(app.RegisterRoutes)\n}\n",
+			value:  "app.RegisterRoutes",
+			stub:   "app.RegisterRoutes",
+		},
+		{
+			desc:    "factory",
+			source:  "import (\n\t\"goyave.dev/goyave/v4\"\n\t\"example/http/route\"\n)\n\nfunc main() {\n\tgoyave.Start(route.NewRegistrer())\n}\n",
+			value:   "route.NewRegistrer()",
+			stub:    "gyvroutes.NewRegistrer()",
+			imports: `gyvroutes "example/http/route"`,
+		},
+		{
+			desc:   "local variable",
+			source: "import (\n\t\"goyave.dev/goyave/v4\"\n\t\"example/http/route\"\n)\n\nfunc main() {\n\tr := route.Register\n\tgoyave.Start(r)\n}\n",
+			err:    "Unsupported route registrer \"r\" passed to \"goyave.Start()\" in main.go:10:15: \"r\" is not a function or variable declared at package level in the main package",
+		},
+		{
+			desc:   "factory with arguments",
+			source: "import (\n\t\"goyave.dev/goyave/v4\"\n\t\"example/http/route\"\n)\n\nfunc main() {\n\tgoyave.Start(route.NewRegistrer(true))\n}\n",
+			err:    "calls with arguments are not supported",
+		},
+		{
+			desc:   "function literal",
+			source: "import \"goyave.dev/goyave/v4\"\n\nfunc main() {\n\tgoyave.Start(func(router *goyave.Router) {})\n}\n",
+			err:    "function literals are not supported",
+		},
+		{
+			desc:   "method value on expression",
+			source: "import \"goyave.dev/goyave/v4\"\n\nfunc main() {\n\tgoyave.Start(NewApp().RegisterRoutes)\n}\n",
+			err:    "method values on expressions are not supported",
+		},
+		{
+			desc:   "no start",
+			source: "func main() {}\n",
+			err:    "Could not find any valid call of \"goyave.Start()\"",
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			dir := t.TempDir()
+			writeTestFile(t, filepath.Join(dir, "main.go"), "package main\n\n"+c.source)
+
+			call, err := FindRouteRegistrer(dir, "goyave.dev/goyave/v4")
+			if c.err != "" {
+				if assert.NotNil(err) {
+					assert.Contains(err.Error(), c.err)
+				}
+				return
+			}
+			if !assert.Nil(err) {
+				return
+			}
+			assert.Equal(c.value, call.Value)
+			data := routeRegistrerStubData(call)
+			assert.Equal(c.stub, data["RouteRegistrer"])
+			assert.Equal(c.imports, data["RouteRegistrerImportPath"])
+
+			routeList, err := stub.GenerateStubVersionPath(stub.InjectRouteList, semver.MustParse("v4.0.0"))
+			assert.Nil(err)
+			for _, name := range []string{stub.InjectOpenAPI, routeList} {
+				data["GoyaveImportPath"] = "goyave.dev/goyave/v4"
+				source, err := stub.Load(name, data)
+				if !assert.Nil(err) {
+					continue
+				}
+				_, err = parser.ParseFile(token.NewFileSet(), "", source.Bytes(), 0)
+				assert.Nil(err)
+				assert.Contains(source.String(), c.stub+"(router)")
+			}
+		})
+	}
+}
+
+func TestFindRouteRegistrerV5(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example\n\ngo 1.16\n")
+	writeTestFile(t, filepath.Join(dir, "http", "route", "route.go"), "package route\n\nfunc Register() {}\n")
+	writeTestFile(t, filepath.Join(dir, "cmd", "api", "main.go"), "package main\n\nimport (\n\t\"goyave.dev/goyave/v5\"\n\t\"example/http/route\"\n)\n\nfunc main() {\n\tserver, _ := goyave.New(goyave.Options{})\n\tserver.RegisterRoutes(route.Register)\n}\n")
+	writeTestFile(t, filepath.Join(dir, "tools", "go.mod"), "module example/tools\n\ngo 1.16\n")
+	writeTestFile(t, filepath.Join(dir, "tools", "main.go"), "package main\n\nimport \"goyave.dev/goyave/v5\"\n\nfunc main() {\n\tserver, _ := goyave.New(goyave.Options{})\n\tserver.RegisterRoutes(register)\n}\n")
+
+	registrer, err := FindRouteRegistrer(dir, "goyave.dev/goyave/v5")
+	if !assert.Nil(err) {
+		return
+	}
+	assert.Equal("route.Register", registrer.Value)
+	assert.Equal("cmd/api", registrer.Directory)
+	assert.True(registrer.TakesServer)
+	assert.Equal("example/http/route.Register (in ./cmd/api)", registrer.String())
+
+	routeList, err := stub.GenerateStubVersionPath(stub.InjectRouteList, semver.MustParse("v5.0.0"))
+	assert.Nil(err)
+	data := routeRegistrerStubData(registrer)
+	data["GoyaveImportPath"] = "goyave.dev/goyave/v5"
+	source, err := stub.Load(routeList, data)
+	if assert.Nil(err) {
+		_, err = parser.ParseFile(token.NewFileSet(), "", source.Bytes(), 0)
+		assert.Nil(err)
+		assert.Contains(source.String(), "gyvroutes.Register(server, router)")
+	}
+
+	// The v4 entry point is ignored for v5 projects
+	_, err = FindRouteRegistrer(dir, "goyave.dev/goyave/v4")
+	assert.NotNil(err)
+
+	writeTestFile(t, filepath.Join(dir, "cmd", "worker", "main.go"), "package main\n\nimport \"goyave.dev/goyave/v5\"\n\nfunc main() {\n\tserver, _ := goyave.New(goyave.Options{})\n\tserver.RegisterRoutes(register)\n}\n\nfunc register(*goyave.Server, *goyave.Router) {}\n")
+	_, err = FindRouteRegistrer(dir, "goyave.dev/goyave/v5")
+	assert.True(errors.Is(err, ErrAmbiguousRouteRegistrer))
+	if assert.NotNil(err) {
+		assert.Contains(err.Error(), "example/http/route.Register (in ./cmd/api), register (in ./cmd/worker)")
+	}
+
+	injector := &Injector{directory: dir, ModFile: &modfile.File{Module: &modfile.Module{Mod: module.Version{Path: "example"}}}, GoyaveImportPath: "goyave.dev/goyave/v5"}
+	registrer, err = injector.findRouteRegistrer("http/route.Register")
+	if assert.Nil(err) {
+		assert.Equal("cmd/api", registrer.Directory)
+		assert.Equal(`gyvroutes "example/http/route"`, routeRegistrerStubData(registrer)["RouteRegistrerImportPath"])
+		assert.Equal("gyvroutes.Register", routeRegistrerStubData(registrer)["RouteRegistrer"])
+	}
+}
+
+func TestParseRouteRegistrer(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "http", "route", "route.go"), "package route\n")
+
+	registrer, err := ParseRouteRegistrer(dir, "example", "goyave.dev/goyave/v4", "example/http/route.Register")
+	if assert.Nil(err) {
+		assert.Equal("route", registrer.Qualifier)
+		assert.Equal("route.Register", registrer.Value)
+		assert.Equal(`"example/http/route"`, registrer.Package.Path.Value)
+		assert.False(registrer.TakesServer)
+	}
+
+	registrer, err = ParseRouteRegistrer(dir, "example", "goyave.dev/goyave/v5", "./http/route.Register")
+	if assert.Nil(err) {
+		assert.Equal(`"example/http/route"`, registrer.Package.Path.Value)
+		assert.True(registrer.TakesServer)
+	}
+
+	registrer, err = ParseRouteRegistrer(dir, "example", "goyave.dev/goyave/v4", "example/http/route/v2.Register")
+	if assert.Nil(err) {
+		assert.Equal("route.Register", registrer.Value)
+	}
+
+	for _, invalid := range []string{"Register", "http/route.", "http/route.register", "http/missing.Register"} {
+		_, err := ParseRouteRegistrer(dir, "example", "goyave.dev/goyave/v4", invalid)
+		assert.NotNil(err, invalid)
+	}
+}
@@ -1,30 +1,121 @@
 package inject
 
-import "goyave.dev/gyv/internal/stub"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"goyave.dev/gyv/internal/stub"
+)
+
+// ErrNoMigrations returned when the project doesn't have any versioned migration.
+var ErrNoMigrations = errors.New("No versioned migration found")
+
+// MigrationSQL the SQL statements executed by the auto-migration
+// of a model or by a versioned migration.
+type MigrationSQL struct {
+	Name       string   `json:"name"`
+	Statements []string `json:"statements"`
+}
 
 // Migrate generate and return database migration function.
-func Migrate(directory string) (func() error, error) {
-	injector, err := NewInjector(directory)
+// The returned function runs auto-migrations, then applies the pending
+// versioned migrations if the project has any, and returns their names.
+func Migrate(directory string, layout DatabaseLayout) (func(ctx context.Context) ([]string, error), error) {
+	injector, err := newMigrateInjector(directory, layout, stub.InjectMigrate)
 	if err != nil {
 		return nil, err
 	}
 
-	modelImportPath := injector.ModFile.Module.Mod.Path + "/database/model"
-	// TODO what if there are sub-directories for models? Blank import them recursively
+	program, err := injector.Inject()
+	if err != nil {
+		return nil, err
+	}
+	var migrate func(context.Context) ([]string, error)
+	if err := program.Lookup("Migrate", &migrate); err != nil {
+		return nil, err
+	}
+	return migrate, nil
+}
+
+// MigrateDryRun generate and return database migration preview function.
+// The returned function doesn't alter the database: it returns the SQL
+// statements the auto-migration of each model and each pending versioned
+// migration would execute.
+func MigrateDryRun(directory string, layout DatabaseLayout) (func(ctx context.Context) ([]*MigrationSQL, error), error) {
+	injector, err := newMigrateInjector(directory, layout, stub.InjectMigrateDryRun)
+	if err != nil {
+		return nil, err
+	}
 
-	injector.StubName = stub.InjectMigrate
+	program, err := injector.Inject()
+	if err != nil {
+		return nil, err
+	}
+	var migrateDryRun func(context.Context) ([]byte, error)
+	if err := program.Lookup("MigrateDryRun", &migrateDryRun); err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) ([]*MigrationSQL, error) {
+		data, err := migrateDryRun(ctx)
+		if err != nil {
+			return nil, err
+		}
+		migrations := []*MigrationSQL{}
+		if err := json.Unmarshal(data, &migrations); err != nil {
+			return nil, err
+		}
+		return migrations, nil
+	}, nil
+}
 
-	injector.StubData = stub.Data{
-		"ModelImportPath": modelImportPath,
+func newMigrateInjector(directory string, layout DatabaseLayout, stubName string) (*Injector, error) {
+	injector, err := NewInjector(directory)
+	if err != nil {
+		return nil, err
 	}
 
-	plug, err := injector.Inject()
+	data, err := migrateStubData(injector, layout)
 	if err != nil {
 		return nil, err
 	}
-	s, err := plug.Lookup("Migrate")
+
+	injector.StubName = stubName
+	injector.StubData = data
+	return injector, nil
+}
+
+func migrateStubData(injector *Injector, layout DatabaseLayout) (stub.Data, error) {
+	modelImportPaths, err := FindPackageImportPaths(injector.directory, injector.ModFile.Module.Mod.Path, layout.ModelRoot)
 	if err != nil {
 		return nil, err
 	}
-	return s.(func() error), nil
+
+	migrationImportPath, err := findMigrationImportPath(injector)
+	if err != nil && !errors.Is(err, ErrNoMigrations) {
+		return nil, err
+	}
+
+	injector.Inputs = append(injector.Inputs, modelPackageInputs(modelImportPaths)...)
+	if migrationImportPath != "" {
+		injector.Inputs = append(injector.Inputs, migrationPackageInput(migrationImportPath, injector.MigrationRoot))
+	}
+
+	return stub.Data{
+		"ModelImportPaths":    modelImportPaths,
+		"MigrationImportPath": migrationImportPath,
+	}, nil
+}
+
+func findMigrationImportPath(injector *Injector) (string, error) {
+	files, err := findGoFiles(filepath.Join(injector.directory, filepath.FromSlash(injector.MigrationRoot)))
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("%w in %q", ErrNoMigrations, injector.MigrationRoot)
+	}
+	return injector.ModFile.Module.Mod.Path + "/" + injector.MigrationRoot, nil
 }
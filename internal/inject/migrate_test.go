@@ -0,0 +1,95 @@
+package inject
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"goyave.dev/gyv/internal/config"
+	"goyave.dev/gyv/internal/stub"
+)
+
+// testDatabaseInjector creates a project with models and seeders in a temporary directory
+// and returns an injector for it. Versioned migrations are only created if "migrations" is true.
+func testDatabaseInjector(t *testing.T, migrations bool) *Injector {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example\n\ngo 1.16\n")
+	writeTestFile(t, filepath.Join(dir, "database", "model", "user.go"), "package model\n\ntype User struct{}\n")
+	writeTestFile(t, filepath.Join(dir, "database", "seeder", "seeder.go"), "package seeder\n\nfunc Run() {}\n")
+	writeTestFile(t, filepath.Join(dir, "database", "seeder", "billing", "billing.go"), "package billing\n\nfunc Invoices() {}\n")
+	if migrations {
+		writeTestFile(t, filepath.Join(dir, "database", "migration", "migration.go"), "package migration\n")
+	}
+	return &Injector{
+		directory:        dir,
+		ModFile:          &modfile.File{Module: &modfile.Module{Mod: module.Version{Path: "example"}}},
+		GoyaveImportPath: "goyave.dev/goyave/v4",
+		MigrationRoot:    config.DefaultMigrationRoot,
+	}
+}
+
+// loadTestStub renders the given stub with the given data and parses the result.
+func loadTestStub(t *testing.T, name string, data stub.Data) (*ast.File, string) {
+	data["GoyaveImportPath"] = "goyave.dev/goyave/v4"
+	source, err := stub.Load(name, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), "", source.Bytes(), 0)
+	if err != nil {
+		t.Fatalf("%s\n%s", err, source.String())
+	}
+	return file, source.String()
+}
+
+func TestMigrateDryRunStub(t *testing.T) {
+	cases := []struct {
+		desc                string
+		migrations          bool
+		migrationImportPath string
+	}{
+		{desc: "auto-migrations only", migrations: false, migrationImportPath: ""},
+		{desc: "versioned migrations", migrations: true, migrationImportPath: "example/database/migration"},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			injector := testDatabaseInjector(t, c.migrations)
+
+			data, err := migrateStubData(injector, DefaultDatabaseLayout)
+			if !assert.Nil(err) {
+				return
+			}
+			assert.Equal([]string{"example/database/model"}, data["ModelImportPaths"])
+			assert.Equal(c.migrationImportPath, data["MigrationImportPath"])
+
+			file, source := loadTestStub(t, stub.InjectMigrateDryRun, data)
+			assert.Equal(c.migrations, strings.Contains(source, "migration.Pending(db)"))
+
+			// The declarations must not conflict with the
+			// identifiers of the project's main package.
+			for _, decl := range file.Decls {
+				switch d := decl.(type) {
+				case *ast.FuncDecl:
+					if d.Recv == nil && d.Name.Name != "MigrateDryRun" {
+						assert.True(strings.HasPrefix(d.Name.Name, "gyv"), d.Name.Name)
+					}
+				case *ast.GenDecl:
+					for _, spec := range d.Specs {
+						if s, ok := spec.(*ast.TypeSpec); ok {
+							assert.True(strings.HasPrefix(s.Name.Name, "gyv"), s.Name.Name)
+						}
+					}
+				}
+			}
+		})
+	}
+}
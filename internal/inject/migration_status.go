@@ -0,0 +1,58 @@
+package inject
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"goyave.dev/gyv/internal/stub"
+)
+
+// MigrationStatus the status of a versioned migration.
+type MigrationStatus struct {
+	MigratedAt *time.Time `json:"migratedAt,omitempty"`
+	Name       string     `json:"name"`
+	Batch      int        `json:"batch,omitempty"`
+	Applied    bool       `json:"applied"`
+}
+
+// MigrationStatuses generate and return the migration status function.
+// The returned function lists all registered and applied versioned migrations.
+func MigrationStatuses(directory string) (func(ctx context.Context) ([]*MigrationStatus, error), error) {
+	injector, err := NewInjector(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	migrationImportPath, err := findMigrationImportPath(injector)
+	if err != nil {
+		return nil, err
+	}
+
+	injector.Inputs = append(injector.Inputs, migrationPackageInput(migrationImportPath, injector.MigrationRoot))
+	injector.StubName = stub.InjectMigrationStatus
+
+	injector.StubData = stub.Data{
+		"MigrationImportPath": migrationImportPath,
+	}
+
+	program, err := injector.Inject()
+	if err != nil {
+		return nil, err
+	}
+	var migrationStatus func(context.Context) ([]byte, error)
+	if err := program.Lookup("MigrationStatus", &migrationStatus); err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) ([]*MigrationStatus, error) {
+		data, err := migrationStatus(ctx)
+		if err != nil {
+			return nil, err
+		}
+		statuses := []*MigrationStatus{}
+		if err := json.Unmarshal(data, &statuses); err != nil {
+			return nil, err
+		}
+		return statuses, nil
+	}, nil
+}
@@ -1,38 +1,60 @@
 package inject
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
 	"github.com/Masterminds/semver"
+	"goyave.dev/gyv/internal/config"
 	"goyave.dev/gyv/internal/stub"
 )
 
 // OpenAPI3Generator injects openapi3 generator into given Goyave project.
-// Returns the injected function.
-func OpenAPI3Generator(directory string) (func() ([]byte, error), error) {
+// If "registrer" is not empty, it is used instead of the route registrer
+// detected in the project (see ParseRouteRegistrer). Returns the injected function,
+// generating the specification in JSON and applying the given metadata (title,
+// version, description, servers and security) to it.
+func OpenAPI3Generator(directory, registrer string) (func(ctx context.Context, metadata config.OpenAPI) ([]byte, error), error) {
 	injector, err := NewInjector(directory)
 	if err != nil {
 		return nil, err
 	}
+	injector.Stdout = os.Stderr
+
+	if goyaveMajorVersion(injector.GoyaveImportPath) >= 5 {
+		return nil, fmt.Errorf("OpenAPI generation is not supported for Goyave %s yet", injector.GoyaveVersion.Original())
+	}
 
-	call, err := FindRouteRegistrer(directory, injector.GoyaveImportPath)
+	call, err := injector.findRouteRegistrer(registrer)
 	if err != nil {
 		return nil, err
 	}
+	injector.PackageDirectory = call.Directory
 
 	libVersion := ""
 	if c, _ := semver.NewConstraint("< v4.0.0-rc1"); c.Check(injector.GoyaveVersion) {
 		libVersion = "v0.1.0"
 	}
 	injector.Dependencies = append(injector.Dependencies, Dependency{"goyave.dev/openapi3", libVersion})
+	injector.Inputs = append(injector.Inputs, routeRegistrerInput(call))
 	injector.StubName = stub.InjectOpenAPI
-	injector.StubData = stub.Data{"RouteRegistrerImportPath": ImportToString(call.Package)}
+	injector.StubData = routeRegistrerStubData(call)
 
-	plug, err := injector.Inject()
+	program, err := injector.Inject()
 	if err != nil {
 		return nil, err
 	}
-	s, err := plug.Lookup("GenerateOpenAPI")
-	if err != nil {
+	var generateOpenAPI func(context.Context, []byte) ([]byte, error)
+	if err := program.Lookup("GenerateOpenAPI", &generateOpenAPI); err != nil {
 		return nil, err
 	}
-	return s.(func() ([]byte, error)), nil
+	return func(ctx context.Context, metadata config.OpenAPI) ([]byte, error) {
+		data, err := json.Marshal(metadata)
+		if err != nil {
+			return nil, err
+		}
+		return generateOpenAPI(ctx, data)
+	}, nil
 }
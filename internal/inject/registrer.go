@@ -0,0 +1,154 @@
+package inject
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrAmbiguousRouteRegistrer returned when different route registrers
+// are found in several main packages of the project.
+var ErrAmbiguousRouteRegistrer = errors.New("Found several route registrers")
+
+// RouteRegistrer the function registering the routes of the application.
+type RouteRegistrer struct {
+	*FunctionCall
+
+	// Directory the slash-separated path to the main package the route
+	// registrer was found in, relative to the project root. Empty for the
+	// project root. The injected code is placed in this package so it can
+	// reference unexported functions and variables.
+	Directory string
+
+	// TakesServer true for Goyave v5 route registrers, taking
+	// the server and the router as parameters.
+	TakesServer bool
+}
+
+// String returns the route registrer and the main package it was found in.
+func (r *RouteRegistrer) String() string {
+	str := r.Value
+	if r.Package != nil {
+		str = strings.Trim(r.Package.Path.Value, `"`) + "." + strings.TrimPrefix(r.Value, r.Qualifier+".")
+	}
+	if r.Directory == "" {
+		return str + " (in ./)"
+	}
+	return str + " (in ./" + r.Directory + ")"
+}
+
+// ParseRouteRegistrer parses a route registrer given by the user in the form
+// "<package>.<Function>" (e.g.: "goyave.dev/app/http/route.Register"). The package
+// can also be a directory relative to the project root (e.g.: "http/route.Register").
+func ParseRouteRegistrer(directory, modulePath, goyaveImportPath, registrer string) (*RouteRegistrer, error) {
+	slash := strings.LastIndex(registrer, "/")
+	dot := strings.LastIndex(registrer, ".")
+	if dot <= slash+1 || dot == len(registrer)-1 {
+		return nil, fmt.Errorf("Invalid route registrer %q: expected \"<package>.<Function>\" (e.g.: \"http/route.Register\")", registrer)
+	}
+	importPath := strings.TrimPrefix(registrer[:dot], "./")
+	function := registrer[dot+1:]
+	if !token.IsIdentifier(function) || !token.IsExported(function) {
+		return nil, fmt.Errorf("Invalid route registrer %q: %q is not an exported function name", registrer, function)
+	}
+
+	if importPath != modulePath && !strings.HasPrefix(importPath, modulePath+"/") {
+		info, err := os.Stat(filepath.Join(directory, filepath.FromSlash(importPath)))
+		if err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("Invalid route registrer %q: package %q not found in the project", registrer, importPath)
+		}
+		importPath = path.Join(modulePath, importPath)
+	}
+
+	qualifier := path.Base(importPath)
+	if isMajorVersion(qualifier) {
+		qualifier = path.Base(path.Dir(importPath))
+	}
+	return &RouteRegistrer{
+		FunctionCall: &FunctionCall{
+			Package: &ast.ImportSpec{
+				Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(importPath)},
+			},
+			Qualifier: qualifier,
+			Value:     qualifier + "." + function,
+		},
+		TakesServer: goyaveMajorVersion(goyaveImportPath) >= 5,
+	}, nil
+}
+
+// findRouteRegistrer returns the route registrer given by the user if not empty,
+// or the route registrer detected in the project. A route registrer given by the
+// user is injected in the first main package of the project.
+func (i *Injector) findRouteRegistrer(registrer string) (*RouteRegistrer, error) {
+	if registrer == "" {
+		return FindRouteRegistrer(i.directory, i.GoyaveImportPath)
+	}
+	r, err := ParseRouteRegistrer(i.directory, i.ModFile.Module.Mod.Path, i.GoyaveImportPath, registrer)
+	if err != nil {
+		return nil, err
+	}
+	r.Directory, err = findMainPackage(i.directory)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// findMainPackage returns the slash-separated path to the first main package
+// of the module in the given directory. Empty if it is the project root.
+func findMainPackage(directory string) (string, error) {
+	packages, err := FindPackages(directory)
+	if err != nil {
+		return "", err
+	}
+	for _, pkg := range packages {
+		files, err := findGoFiles(filepath.Join(directory, filepath.FromSlash(pkg)))
+		if err != nil {
+			return "", err
+		}
+		for _, f := range files {
+			if strings.HasSuffix(f, "_test.go") {
+				continue
+			}
+			astFile, err := parser.ParseFile(token.NewFileSet(), f, nil, parser.PackageClauseOnly)
+			if err != nil {
+				return "", err
+			}
+			if astFile.Name.Name != "main" {
+				break
+			}
+			if pkg == "." {
+				return "", nil
+			}
+			return pkg, nil
+		}
+	}
+	return "", fmt.Errorf("Could not find any main package in %s", directory)
+}
+
+// goyaveMajorVersion returns the major version of Goyave from its import path
+// (e.g.: 4 for "goyave.dev/goyave/v4").
+func goyaveMajorVersion(goyaveImportPath string) int {
+	base := path.Base(goyaveImportPath)
+	if !isMajorVersion(base) {
+		return 1
+	}
+	major, _ := strconv.Atoi(base[1:])
+	return major
+}
+
+func importsPackage(imports []*ast.ImportSpec, importPath string) bool {
+	for _, i := range imports {
+		if path, err := strconv.Unquote(i.Path.Value); err == nil && path == importPath {
+			return true
+		}
+	}
+	return false
+}
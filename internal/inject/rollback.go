@@ -0,0 +1,40 @@
+package inject
+
+import (
+	"context"
+
+	"goyave.dev/gyv/internal/stub"
+)
+
+// Rollback generate and return migration rollback function.
+// The returned function reverts the given number of most recently
+// applied versioned migrations (or the last batch if "steps" is lower
+// than 1) and returns their names.
+func Rollback(directory string) (func(ctx context.Context, steps int) ([]string, error), error) {
+	injector, err := NewInjector(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	migrationImportPath, err := findMigrationImportPath(injector)
+	if err != nil {
+		return nil, err
+	}
+
+	injector.Inputs = append(injector.Inputs, migrationPackageInput(migrationImportPath, injector.MigrationRoot))
+	injector.StubName = stub.InjectRollback
+
+	injector.StubData = stub.Data{
+		"MigrationImportPath": migrationImportPath,
+	}
+
+	program, err := injector.Inject()
+	if err != nil {
+		return nil, err
+	}
+	var rollback func(context.Context, int) ([]string, error)
+	if err := program.Lookup("Rollback", &rollback); err != nil {
+		return nil, err
+	}
+	return rollback, nil
+}
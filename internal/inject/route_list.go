@@ -0,0 +1,65 @@
+package inject
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"goyave.dev/gyv/internal/stub"
+)
+
+// Route the description of a route registered in a Goyave project's router.
+type Route struct {
+	Methods    []string `json:"methods"`
+	URI        string   `json:"uri"`
+	Name       string   `json:"name"`
+	Handler    string   `json:"handler"`
+	Middleware []string `json:"middleware"`
+}
+
+// RouteList injects a route listing function into given Goyave project.
+// The injected function builds the project's main router and returns
+// all its routes, including the ones registered in subrouters.
+// If "registrer" is not empty, it is used instead of the route registrer
+// detected in the project (see ParseRouteRegistrer).
+func RouteList(directory, registrer string) (func(ctx context.Context) ([]*Route, error), error) {
+	injector, err := NewInjector(directory)
+	if err != nil {
+		return nil, err
+	}
+	injector.Stdout = os.Stderr
+
+	call, err := injector.findRouteRegistrer(registrer)
+	if err != nil {
+		return nil, err
+	}
+	injector.PackageDirectory = call.Directory
+
+	injector.Inputs = append(injector.Inputs, routeRegistrerInput(call))
+	stubName, err := stub.GenerateStubVersionPath(stub.InjectRouteList, injector.GoyaveVersion)
+	if err != nil {
+		return nil, err
+	}
+	injector.StubName = stubName
+	injector.StubData = routeRegistrerStubData(call)
+
+	program, err := injector.Inject()
+	if err != nil {
+		return nil, err
+	}
+	var listRoutes func(context.Context) ([]byte, error)
+	if err := program.Lookup("ListRoutes", &listRoutes); err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) ([]*Route, error) {
+		data, err := listRoutes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		routes := []*Route{}
+		if err := json.Unmarshal(data, &routes); err != nil {
+			return nil, err
+		}
+		return routes, nil
+	}, nil
+}
@@ -1,41 +1,95 @@
 package inject
 
 import (
-	"path"
+	"context"
+	"fmt"
+	"strconv"
 
 	"goyave.dev/gyv/internal/stub"
 )
 
 // Seeder generate and return database seed function.
-func Seeder(directory string, seeders []string) (func() error, error) {
+// The returned function runs the given seeders in order.
+func Seeder(directory string, layout DatabaseLayout, seeders []*SeederFunction) (func(ctx context.Context) error, error) {
 	injector, err := NewInjector(directory)
 	if err != nil {
 		return nil, err
 	}
 
-	seederImportPath := injector.ModFile.Module.Mod.Path + "/database/seeder"
-
 	injector.StubName = stub.InjectSeeder
 
-	blankImports, err := GetBlankImports(directory)
+	injector.StubData, err = seederStubData(injector, layout, seeders)
 	if err != nil {
 		return nil, err
 	}
 
-	injector.StubData = stub.Data{
-		"BlankImports":     blankImports,
-		"SeederImportPath": seederImportPath,
-		"SeederPackage":    path.Base(seederImportPath),
-		"Seeders":          seeders,
+	program, err := injector.Inject()
+	if err != nil {
+		return nil, err
+	}
+	var seed func(context.Context) error
+	if err := program.Lookup("Seed", &seed); err != nil {
+		return nil, err
 	}
+	return seed, nil
+}
+
+type seederPackage struct {
+	Alias      string
+	ImportPath string
+}
 
-	plug, err := injector.Inject()
+func seederStubData(injector *Injector, layout DatabaseLayout, seeders []*SeederFunction) (stub.Data, error) {
+	blankImports, err := GetBlankImports(injector.directory)
 	if err != nil {
 		return nil, err
 	}
-	s, err := plug.Lookup("Seed")
+
+	modelImportPaths, err := FindPackageImportPaths(injector.directory, injector.ModFile.Module.Mod.Path, layout.ModelRoot)
 	if err != nil {
 		return nil, err
 	}
-	return s.(func() error), nil
+	for _, p := range modelImportPaths {
+		quoted := strconv.Quote(p)
+		if !contains(blankImports, quoted) {
+			blankImports = append(blankImports, quoted)
+		}
+	}
+
+	// Seeder packages are aliased because packages in different
+	// directories can have the same name.
+	packages := []seederPackage{}
+	aliases := map[string]string{}
+	calls := make([]string, 0, len(seeders))
+	for _, s := range seeders {
+		alias, ok := aliases[s.ImportPath]
+		if !ok {
+			alias = fmt.Sprintf("seeder%d", len(packages))
+			aliases[s.ImportPath] = alias
+			packages = append(packages, seederPackage{Alias: alias, ImportPath: s.ImportPath})
+		}
+		calls = append(calls, alias+"."+s.Function)
+		injector.Inputs = append(injector.Inputs, &Input{
+			Name:   "seeder",
+			Value:  alias + "." + s.Function,
+			Origin: s.String(),
+			Hint:   fmt.Sprintf("Seeders must be exported functions of package %q without parameters nor return values.", s.ImportPath),
+		})
+	}
+	injector.Inputs = append(injector.Inputs, modelPackageInputs(modelImportPaths)...)
+
+	return stub.Data{
+		"BlankImports":   blankImports,
+		"SeederPackages": packages,
+		"Seeders":        calls,
+	}, nil
+}
+
+func contains(slice []string, value string) bool {
+	for _, v := range slice {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
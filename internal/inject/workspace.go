@@ -0,0 +1,105 @@
+package inject
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// workspace a temporary directory holding the injected source files and
+// copies of the project's "go.mod" and "go.sum". The go commands use these
+// copies thanks to the "-modfile" flag and see the injected files in the
+// project thanks to the "-overlay" flag, so the files of the project
+// are never modified.
+type workspace struct {
+	directory        string
+	projectDirectory string
+	overlay          map[string]string
+	kept             bool
+}
+
+func newWorkspace(projectDirectory string) (*workspace, error) {
+	projectDirectory, err := filepath.Abs(projectDirectory)
+	if err != nil {
+		return nil, err
+	}
+	directory, err := os.MkdirTemp("", "gyv-inject-")
+	if err != nil {
+		return nil, err
+	}
+	registerTemporaryFile(directory)
+	w := &workspace{
+		directory:        directory,
+		projectDirectory: projectDirectory,
+		overlay:          map[string]string{},
+	}
+
+	for _, name := range []string{"go.mod", "go.sum"} {
+		if err := copyFile(filepath.Join(projectDirectory, name), filepath.Join(directory, name)); err != nil && !os.IsNotExist(err) {
+			_ = w.remove()
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// addFile writes a source file to the workspace and makes it appear
+// in the project at the given path, relative to the project's root.
+func (w *workspace) addFile(name string, source []byte) error {
+	path := filepath.Join(w.directory, filepath.Base(name))
+	if err := writeTemporaryFile(path, source); err != nil {
+		return err
+	}
+	w.overlay[filepath.Join(w.projectDirectory, name)] = path
+	return nil
+}
+
+// modFileFlag returns the go command flag making it use the
+// workspace's copy of "go.mod" and "go.sum".
+func (w *workspace) modFileFlag() string {
+	return "-modfile=" + filepath.Join(w.directory, "go.mod")
+}
+
+// overlayFlag writes the overlay file and returns the go command flag
+// making it see the workspace's source files in the project.
+// Must be called after all files have been added.
+func (w *workspace) overlayFlag() (string, error) {
+	overlayPath := filepath.Join(w.directory, "overlay.json")
+	data, err := json.Marshal(map[string]interface{}{"Replace": w.overlay})
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(overlayPath, data, 0644); err != nil {
+		return "", err
+	}
+	return "-overlay=" + overlayPath, nil
+}
+
+// keep prevents the workspace from being removed, even by "Cleanup()".
+func (w *workspace) keep() {
+	unregisterTemporaryFile(w.directory)
+	w.kept = true
+}
+
+func (w *workspace) remove() error {
+	return removeTemporaryFile(w.directory)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
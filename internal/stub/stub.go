@@ -23,6 +23,21 @@ const (
 	Middleware = "embed/middleware"
 	// Model is the path to model stubs
 	Model = "embed/model"
+	// CRUD is the path to the CRUD resource stubs
+	CRUD = "embed/crud"
+	// CRUDModel is the path to the CRUD model stubs
+	CRUDModel = CRUD + "/model"
+	// CRUDController is the path to the CRUD controller stubs
+	CRUDController = CRUD + "/controller"
+	// CRUDRequest is the path to the CRUD validation rule sets stubs
+	CRUDRequest = CRUD + "/request"
+	// Migration is the path to the versioned migration stubs
+	Migration = "embed/migration"
+	// MigrationFile is the path to the versioned migration file stubs
+	MigrationFile = Migration + "/file"
+	// MigrationRegistry is the path to the stubs of the migration registry,
+	// generated in the project alongside the first migration
+	MigrationRegistry = Migration + "/registry"
 	// Inject is the path to the inject stubs
 	Inject = "embed/inject"
 	// InjectOpenAPI is the path to the injected OpenAPI generator stub
@@ -31,8 +46,21 @@ const (
 	InjectSeeder = Inject + "/seed.go.stub"
 	// InjectMigrate is the path to the injected database migration function
 	InjectMigrate = Inject + "/migrate.go.stub"
+	// InjectMigrateDryRun is the path to the injected function capturing the migrations SQL
+	InjectMigrateDryRun = Inject + "/migrate_dry_run.go.stub"
+	// InjectRollback is the path to the injected migration rollback function
+	InjectRollback = Inject + "/rollback.go.stub"
+	// InjectMigrationStatus is the path to the injected migration status function
+	InjectMigrationStatus = Inject + "/migration_status.go.stub"
 	// InjectDBClear is the path to the injected database clear function
 	InjectDBClear = Inject + "/db_clear.go.stub"
+	// InjectFresh is the path to the injected database reset function
+	InjectFresh = Inject + "/fresh.go.stub"
+	// InjectRouteList is the path to the versioned injected route list function stubs
+	InjectRouteList = Inject + "/route_list"
+	// InjectExecMain is the path to the file added to the injected code when it
+	// is built as an executable, handling the requests sent by gyv
+	InjectExecMain = Inject + "/exec_main.go.stub"
 )
 
 // Data represent the data to inject inside stub files
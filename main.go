@@ -1,10 +1,18 @@
 package main
 
 import (
+	"os"
+
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"goyave.dev/gyv/internal/command"
+	"goyave.dev/gyv/internal/command/cache"
 	"goyave.dev/gyv/internal/command/create"
 	"goyave.dev/gyv/internal/command/db"
 	"goyave.dev/gyv/internal/command/openapi"
+	"goyave.dev/gyv/internal/command/route"
+	"goyave.dev/gyv/internal/command/versions"
+	"goyave.dev/gyv/internal/inject"
 )
 
 func buildRootCommand() *cobra.Command {
@@ -16,10 +24,43 @@ func buildRootCommand() *cobra.Command {
 All commands can be run either in interactive mode or using POSIX flags.`,
 	}
 
+	gyv.PersistentFlags().StringVar(
+		&command.Environment,
+		"env",
+		"",
+		"The environment of the configuration loaded by commands running the project's code (e.g.: \"staging\" for \"config.staging.json\")",
+	)
+
+	gyv.PersistentFlags().DurationVar(
+		&command.Timeout,
+		"timeout",
+		0,
+		"Cancel the execution of the project's code after this duration (e.g.: \"30s\", \"5m\"). No timeout by default",
+	)
+
+	gyv.PersistentFlags().BoolVar(
+		&inject.NoCache,
+		"no-cache",
+		false,
+		"Always build the plugin of commands running the project's code instead of using the plugin cache",
+	)
+
+	gyv.PersistentFlags().StringVar(
+		(*string)(&inject.DefaultBackend),
+		"backend",
+		string(inject.BackendAuto),
+		"How commands run the project's code: \"plugin\" loads it as a Go plugin, \"exec\" runs it as a separate executable, \"auto\" uses exec if the plugin cannot be loaded",
+	)
+
+	setBuildFlags(gyv.PersistentFlags())
+
 	commands := []*cobra.Command{
 		create.BuildCommand(),
 		db.BuildCommand(),
 		(&openapi.OpenAPI{}).BuildCobraCommand(),
+		route.BuildCommand(),
+		cache.BuildCommand(),
+		(&versions.Versions{}).BuildCobraCommand(),
 	}
 
 	for _, c := range commands {
@@ -29,11 +70,53 @@ All commands can be run either in interactive mode or using POSIX flags.`,
 	return gyv
 }
 
-func execute() {
+func setBuildFlags(flags *pflag.FlagSet) {
+	flags.StringSliceVar(
+		&inject.DefaultBuildOptions.Tags,
+		"tags",
+		[]string{},
+		"The build tags used when building the project's code (overrides the \"build.tags\" entry of gyv.json)",
+	)
+	flags.StringVar(
+		&inject.DefaultBuildOptions.GoFlags,
+		"goflags",
+		"",
+		"Flags added to GOFLAGS when building the project's code (overrides the \"build.goflags\" entry of gyv.json)",
+	)
+	flags.StringVar(
+		&inject.DefaultBuildOptions.Go,
+		"go",
+		"",
+		"The path to the go binary used to build the project's code (overrides the \"build.go\" entry of gyv.json)",
+	)
+	flags.StringVar(
+		&inject.DefaultBuildOptions.Toolchain,
+		"toolchain",
+		"",
+		"The GOTOOLCHAIN used to build the project's code (overrides the \"build.toolchain\" entry of gyv.json)",
+	)
+	flags.BoolVar(
+		&inject.KeepTemp,
+		"keep-temp",
+		false,
+		"Keep the generated source files when building the project's code fails",
+	)
+	flags.BoolVar(
+		&inject.DefaultBuildOptions.Vendor,
+		"vendor",
+		false,
+		"Build the project's code using its vendor directory (or set the \"build.vendor\" entry of gyv.json)",
+	)
+}
+
+func execute() int {
+	inject.CleanupOnSignal()
+	defer inject.Cleanup()
 	rootCommand := buildRootCommand()
 	_ = rootCommand.Execute()
+	return command.ExitCode
 }
 
 func main() {
-	execute()
+	os.Exit(execute())
 }